@@ -0,0 +1,35 @@
+// Description: This file contains a cache for API lists, which are looked up often during a plan
+
+package auxo
+
+import (
+	"context"
+	"sync"
+)
+
+// listCache caches the result of an API list call for the lifetime of the provider
+type listCache[T any] struct {
+	m      sync.Mutex
+	items  []*T
+	loaded bool
+}
+
+// get returns the cached items, the items are loaded with load on first use
+func (c *listCache[T]) get(ctx context.Context, load func(context.Context) ([]*T, error)) ([]*T, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.loaded {
+		return c.items, nil
+	}
+
+	items, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.items = items
+	c.loaded = true
+
+	return c.items, nil
+}
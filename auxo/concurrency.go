@@ -0,0 +1,160 @@
+// Description: This file contains the read-modify-write cycle of a protect surface, which detects concurrent
+// modifications (e.g. in the portal) between reading and writing the protect surface
+//
+// The API has no version or etag of a protect surface, so the managed fields are compared with the prior Terraform
+// state and the protect surface is read again just before it is written and compared with the version which was modified.
+
+package auxo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/on2itsecurity/go-auxo/v2"
+	"github.com/on2itsecurity/go-auxo/v2/zerotrust"
+)
+
+// maxUpdateAttempts is the number of times a protect surface is modified, when it is modified concurrently
+const maxUpdateAttempts = 3
+
+// protectSurfaceUpdate is a change of the fields of a protect surface managed by a resource
+type protectSurfaceUpdate struct {
+	id string
+	// managed are the (JSON) fields of the protect surface set by modify
+	managed []string
+	// drift returns the attributes whose value on the protect surface differs from the prior Terraform state,
+	// nil when there is no prior state (create) or it is not compared (delete)
+	drift func(ps *zerotrust.ProtectSurface) []string
+	// ignoreMissing returns without error, when the protect surface does not exist (anymore)
+	ignoreMissing bool
+	modify        func(ps *zerotrust.ProtectSurface) diag.Diagnostics
+}
+
+// updateProtectSurface reads the protect surface, modifies it and writes it back
+// Before the protect surface is modified, the managed fields are compared with the prior Terraform state, so changes
+// made since the last refresh (e.g. in the portal) are not overwritten. When other fields are changed between reading
+// and writing, modify is applied again on the new version of the protect surface. When managed fields are changed, or
+// the protect surface keeps changing, an error is returned which names the conflicting fields.
+// The API has no preconditions on an update, so a change between the last read and the write can not be detected.
+// When ignoreMissing is set and the protect surface does not exist, nil is returned without errors.
+func updateProtectSurface(ctx context.Context, client *auxo.Client, u protectSurfaceUpdate) (*zerotrust.ProtectSurface, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var changed []string
+
+	for attempt := 1; attempt <= maxUpdateAttempts; attempt++ {
+		ps, err := client.ZeroTrust.GetProtectSurfaceByID(ctx, u.id)
+		if u.ignoreMissing && isNotFoundError(err) {
+			tflog.Debug(ctx, "Protectsurface already deleted", map[string]interface{}{"protectsurface": u.id})
+			return nil, diags
+		}
+		if err != nil {
+			diags.AddError("Error reading protect surface", "unexpected error: "+err.Error())
+			return nil, diags
+		}
+
+		if u.drift != nil {
+			if drifted := u.drift(ps); len(drifted) > 0 {
+				diags.AddError("Protect surface modified outside Terraform",
+					fmt.Sprintf("Protect surface %s was modified by someone else (e.g. in the portal) since it was last refreshed, "+
+						"the conflicting attributes are [%s]. Refresh and apply again.", u.id, strings.Join(drifted, ", ")))
+				return nil, diags
+			}
+		}
+
+		original, err := json.Marshal(ps)
+		if err != nil {
+			diags.AddError("Error reading protect surface", "unexpected error: "+err.Error())
+			return nil, diags
+		}
+
+		diags.Append(u.modify(ps)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		// Check the protect surface did not change, since it was read
+		current, err := client.ZeroTrust.GetProtectSurfaceByID(ctx, u.id)
+		if err != nil {
+			diags.AddError("Error reading protect surface", "unexpected error: "+err.Error())
+			return nil, diags
+		}
+
+		changed, err = changedFields(original, current)
+		if err != nil {
+			diags.AddError("Error reading protect surface", "unexpected error: "+err.Error())
+			return nil, diags
+		}
+
+		if len(changed) == 0 {
+			result, err := client.ZeroTrust.UpdateProtectSurface(ctx, *ps)
+			if err != nil {
+				diags.AddError("Error updating protect surface", "unexpected error: "+err.Error())
+				return nil, diags
+			}
+
+			return result, diags
+		}
+
+		if conflicts := intersect(changed, u.managed); len(conflicts) > 0 {
+			diags.AddError("Protect surface modified concurrently",
+				fmt.Sprintf("Protect surface %s was modified by someone else (e.g. in the portal) while it was being updated, "+
+					"the conflicting fields are [%s]. Refresh and apply again.", u.id, strings.Join(conflicts, ", ")))
+			return nil, diags
+		}
+	}
+
+	diags.AddError("Protect surface modified concurrently",
+		fmt.Sprintf("Protect surface %s kept changing while it was being updated (%d attempts), the changed fields are [%s]. "+
+			"Refresh and apply again.", u.id, maxUpdateAttempts, strings.Join(changed, ", ")))
+
+	return nil, diags
+}
+
+// changedFields returns the sorted (JSON) fields which differ between the JSON encoded original and the current protect surface
+func changedFields(original []byte, current *zerotrust.ProtectSurface) ([]string, error) {
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var before, after map[string]interface{}
+	if err := json.Unmarshal(original, &before); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(currentJSON, &after); err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for k, v := range before {
+		if !reflect.DeepEqual(v, after[k]) {
+			changed = append(changed, k)
+		}
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+
+	return changed, nil
+}
+
+// intersect returns the elements of a which are also in b
+func intersect(a, b []string) []string {
+	var result []string
+	for _, s := range a {
+		if sliceContains(b, s) {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,492 @@
+package auxo
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/on2itsecurity/go-auxo/v2"
+	"github.com/on2itsecurity/go-auxo/v2/zerotrust"
+)
+
+// TestMain trusts the certificate of the httptest servers, since go-auxo verifies the API with the system certificates
+func TestMain(m *testing.M) {
+	certFile, err := writeTestServerCertificate()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Setenv("SSL_CERT_FILE", certFile)
+
+	code := m.Run()
+	os.Remove(certFile)
+	os.Exit(code)
+}
+
+// writeTestServerCertificate writes the certificate of the httptest servers, which is the same for every server, to a
+// temporary file and returns its name
+func writeTestServerCertificate() (string, error) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	f, err := os.CreateTemp("", "auxo-test-*.pem")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}); err != nil {
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// fakeAPI is an in-memory AUXO API with the protect surface calls
+type fakeAPI struct {
+	mu              sync.Mutex
+	protectsurfaces map[string]*zerotrust.ProtectSurface
+	// getError is returned on every get of a protect surface, when set
+	getError *fakeAPIError
+	// afterGet is called after every get of a protect surface, with the number of gets, e.g. to modify it concurrently
+	afterGet func(gets int, ps *zerotrust.ProtectSurface)
+	gets     int
+	updates  int
+}
+
+// fakeAPIError is the status and body of an error response
+type fakeAPIError struct {
+	status int
+	body   string
+}
+
+// newFakeAPI starts the fake API with the protect surfaces and returns a client for it
+func newFakeAPI(t *testing.T, protectsurfaces ...*zerotrust.ProtectSurface) (*fakeAPI, *auxo.Client) {
+	t.Helper()
+
+	api := &fakeAPI{protectsurfaces: map[string]*zerotrust.ProtectSurface{}}
+	for _, ps := range protectsurfaces {
+		api.protectsurfaces[ps.ID] = ps
+	}
+
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("SSL_CERT_FILE is not used for the system certificates on " + runtime.GOOS)
+	}
+
+	//go-auxo only speaks HTTP/2
+	server := httptest.NewUnstartedServer(api)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	client, err := auxo.NewClient(server.Listener.Addr().String(), "secret-token", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return api, client
+}
+
+func (a *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var items []*zerotrust.ProtectSurface
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/get-protectsurface"):
+		a.gets++
+		if a.getError != nil {
+			http.Error(w, a.getError.body, a.getError.status)
+			return
+		}
+
+		if ps, ok := a.protectsurfaces[r.URL.Query().Get("id")]; ok {
+			items = append(items, copyProtectSurface(ps))
+			if a.afterGet != nil {
+				a.afterGet(a.gets, ps)
+			}
+		}
+	case strings.HasSuffix(r.URL.Path, "/create-protectsurface"), strings.HasSuffix(r.URL.Path, "/create-or-replace-protectsurface"):
+		var body struct {
+			Items []*zerotrust.ProtectSurface `json:"items"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Items) != 1 {
+			http.Error(w, `{"error_id":"400","error_name":"Bad request"}`, http.StatusBadRequest)
+			return
+		}
+
+		a.updates++
+		a.protectsurfaces[body.Items[0].ID] = body.Items[0]
+		items = body.Items
+	default:
+		http.Error(w, `{"error_id":"404","error_name":"Not found"}`, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+}
+
+// copyProtectSurface returns a deep copy of the protect surface, so it is not modified by the caller
+func copyProtectSurface(ps *zerotrust.ProtectSurface) *zerotrust.ProtectSurface {
+	data, _ := json.Marshal(ps)
+	var c zerotrust.ProtectSurface
+	_ = json.Unmarshal(data, &c)
+
+	return &c
+}
+
+// setMeasures returns a protect surface update which sets the measures
+func setMeasures(id string, measures map[string]zerotrust.MeasureState) protectSurfaceUpdate {
+	return protectSurfaceUpdate{
+		id:      id,
+		managed: []string{"measures"},
+		modify: func(ps *zerotrust.ProtectSurface) diag.Diagnostics {
+			ps.Measures = measures
+			return nil
+		},
+	}
+}
+
+func assignedMeasure(assigned bool) zerotrust.MeasureState {
+	return zerotrust.MeasureState{Assignment: &zerotrust.Assignment{Assigned: assigned, LastDeterminedByPersonID: "terraform", LastDeterminedTimestamp: 1700000000}}
+}
+
+func TestUpdateProtectSurface(t *testing.T) {
+	api, client := newFakeAPI(t, &zerotrust.ProtectSurface{ID: "ps1", Name: "web"})
+
+	ps, diags := updateProtectSurface(context.Background(), client,
+		setMeasures("ps1", map[string]zerotrust.MeasureState{"m1": assignedMeasure(true)}))
+	if diags.HasError() {
+		t.Fatalf("unexpected error %v", diags)
+	}
+
+	if ps.Measures["m1"].Assignment == nil || !ps.Measures["m1"].Assignment.Assigned {
+		t.Errorf("expected measure m1 to be assigned, got %+v", ps.Measures)
+	}
+	if api.gets != 2 || api.updates != 1 {
+		t.Errorf("expected 2 gets and 1 update, got %d gets and %d updates", api.gets, api.updates)
+	}
+}
+
+func TestUpdateProtectSurfaceRetry(t *testing.T) {
+	api, client := newFakeAPI(t, &zerotrust.ProtectSurface{ID: "ps1", Name: "web"})
+
+	// Someone changes the description (not managed) while the measures are set
+	api.afterGet = func(gets int, ps *zerotrust.ProtectSurface) {
+		if gets == 1 {
+			ps.Description = "changed in the portal"
+		}
+	}
+
+	ps, diags := updateProtectSurface(context.Background(), client,
+		setMeasures("ps1", map[string]zerotrust.MeasureState{"m1": assignedMeasure(true)}))
+	if diags.HasError() {
+		t.Fatalf("unexpected error %v", diags)
+	}
+
+	if ps.Description != "changed in the portal" {
+		t.Errorf("expected the concurrent change of the description to be kept, got %q", ps.Description)
+	}
+	if _, ok := ps.Measures["m1"]; !ok {
+		t.Errorf("expected measure m1 to be set, got %+v", ps.Measures)
+	}
+	if api.gets != 4 || api.updates != 1 {
+		t.Errorf("expected 4 gets (retried once) and 1 update, got %d gets and %d updates", api.gets, api.updates)
+	}
+}
+
+func TestUpdateProtectSurfaceKeepsChanging(t *testing.T) {
+	api, client := newFakeAPI(t, &zerotrust.ProtectSurface{ID: "ps1", Name: "web"})
+
+	api.afterGet = func(gets int, ps *zerotrust.ProtectSurface) {
+		ps.Description += "."
+	}
+
+	_, diags := updateProtectSurface(context.Background(), client,
+		setMeasures("ps1", map[string]zerotrust.MeasureState{"m1": assignedMeasure(true)}))
+	if !diags.HasError() || !strings.Contains(diags[0].Detail(), "kept changing") || !strings.Contains(diags[0].Detail(), "description") {
+		t.Fatalf("expected an error that the protect surface kept changing, got %v", diags)
+	}
+	if api.updates != 0 {
+		t.Errorf("expected no update, got %d", api.updates)
+	}
+}
+
+func TestUpdateProtectSurfaceConflict(t *testing.T) {
+	api, client := newFakeAPI(t, &zerotrust.ProtectSurface{ID: "ps1", Name: "web"})
+
+	// Someone assigns a measure (managed) while the measures are set
+	api.afterGet = func(gets int, ps *zerotrust.ProtectSurface) {
+		ps.Measures = map[string]zerotrust.MeasureState{"m2": assignedMeasure(true)}
+	}
+
+	_, diags := updateProtectSurface(context.Background(), client,
+		setMeasures("ps1", map[string]zerotrust.MeasureState{"m1": assignedMeasure(true)}))
+	if !diags.HasError() || diags[0].Summary() != "Protect surface modified concurrently" || !strings.Contains(diags[0].Detail(), "[measures]") {
+		t.Fatalf("expected a conflict on measures, got %v", diags)
+	}
+	if api.updates != 0 {
+		t.Errorf("expected no update, got %d", api.updates)
+	}
+}
+
+func TestUpdateProtectSurfaceMeasuresDrift(t *testing.T) {
+	prior := getMeasuresFromMap(map[string]zerotrust.MeasureState{"m1": assignedMeasure(true)})
+
+	tests := map[string]struct {
+		current  map[string]zerotrust.MeasureState
+		expected string
+	}{
+		"unchanged": {current: map[string]zerotrust.MeasureState{"m1": assignedMeasure(true)}},
+		"changed":   {current: map[string]zerotrust.MeasureState{"m1": assignedMeasure(false)}, expected: "[measures.m1]"},
+		"added": {
+			current:  map[string]zerotrust.MeasureState{"m1": assignedMeasure(true), "m2": assignedMeasure(true)},
+			expected: "[measures.m2]",
+		},
+		"removed": {current: map[string]zerotrust.MeasureState{}, expected: "[measures.m1]"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			api, client := newFakeAPI(t, &zerotrust.ProtectSurface{ID: "ps1", Name: "web", Measures: test.current})
+
+			update := setMeasures("ps1", map[string]zerotrust.MeasureState{"m1": assignedMeasure(false)})
+			update.drift = measuresDrift(prior)
+
+			_, diags := updateProtectSurface(context.Background(), client, update)
+			if test.expected == "" {
+				if diags.HasError() || api.updates != 1 {
+					t.Fatalf("expected 1 update without errors, got %d updates and %v", api.updates, diags)
+				}
+				return
+			}
+
+			if !diags.HasError() || diags[0].Summary() != "Protect surface modified outside Terraform" || !strings.Contains(diags[0].Detail(), test.expected) {
+				t.Fatalf("expected a conflict on %s, got %v", test.expected, diags)
+			}
+			if api.updates != 0 {
+				t.Errorf("expected no update, got %d", api.updates)
+			}
+		})
+	}
+}
+
+func TestUpdateProtectSurfaceFlowsDrift(t *testing.T) {
+	ctx := context.Background()
+
+	prior := transactionflowResourceModel{
+		Protectsurface:                 types.StringValue("ps1"),
+		Incoming_protectsurfaces_allow: types.SetValueMust(types.StringType, nil),
+		Incoming_protectsurfaces_block: types.SetValueMust(types.StringType, nil),
+		Incoming_protectsurfaces_unset: types.SetValueMust(types.StringType, nil),
+		Outgoing_protectsurfaces_allow: types.SetValueMust(types.StringType, []attr.Value{types.StringValue("ps2")}),
+		Outgoing_protectsurfaces_block: types.SetValueMust(types.StringType, nil),
+		// A state of an older version of the provider, without the unset flows
+		Outgoing_protectsurfaces_unset: types.SetNull(types.StringType),
+	}
+
+	allowFlow := func(ps *zerotrust.ProtectSurface) diag.Diagnostics {
+		ps.FlowsToOtherPS = map[string]zerotrust.Flow{"ps2": {Allow: boolPtr(true)}, "ps3": {Allow: boolPtr(true)}}
+		return nil
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		api, client := newFakeAPI(t, &zerotrust.ProtectSurface{ID: "ps1", FlowsToOtherPS: map[string]zerotrust.Flow{"ps2": {Allow: boolPtr(true)}}})
+
+		_, diags := updateProtectSurface(ctx, client, protectSurfaceUpdate{id: "ps1", managed: flowFields, drift: flowsDrift(ctx, &prior), modify: allowFlow})
+		if diags.HasError() || api.updates != 1 {
+			t.Fatalf("expected 1 update without errors, got %d updates and %v", api.updates, diags)
+		}
+	})
+
+	t.Run("blocked in the portal", func(t *testing.T) {
+		api, client := newFakeAPI(t, &zerotrust.ProtectSurface{ID: "ps1", FlowsToOtherPS: map[string]zerotrust.Flow{"ps2": {Allow: boolPtr(false)}}})
+
+		_, diags := updateProtectSurface(ctx, client, protectSurfaceUpdate{id: "ps1", managed: flowFields, drift: flowsDrift(ctx, &prior), modify: allowFlow})
+		if !diags.HasError() || !strings.Contains(diags[0].Detail(), "[outgoing_protectsurfaces_allow, outgoing_protectsurfaces_block]") {
+			t.Fatalf("expected a conflict on the outgoing flows, got %v", diags)
+		}
+		if api.updates != 0 {
+			t.Errorf("expected no update, got %d", api.updates)
+		}
+	})
+}
+
+func TestUpdateProtectSurfaceNotFound(t *testing.T) {
+	_, client := newFakeAPI(t)
+
+	update := setMeasures("ps1", map[string]zerotrust.MeasureState{})
+
+	_, diags := updateProtectSurface(context.Background(), client, update)
+	if !diags.HasError() || !strings.Contains(diags[0].Detail(), "No protectsurface found") {
+		t.Fatalf("expected a not found error, got %v", diags)
+	}
+
+	update.ignoreMissing = true
+	ps, diags := updateProtectSurface(context.Background(), client, update)
+	if diags.HasError() || ps != nil {
+		t.Fatalf("expected nil without errors when missing protect surfaces are ignored, got %v and %v", ps, diags)
+	}
+}
+
+// newTestState returns the state of the resource, set to the model
+func newTestState(t *testing.T, r resource.Resource, model interface{}) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	if diags := state.Set(ctx, model); diags.HasError() {
+		t.Fatalf("unable to set state: %v", diags)
+	}
+
+	return state
+}
+
+// protectSurfacePartResources are the resources which manage a part of a protect surface, with their state, the
+// protect surface with that part and whether the part is removed from the protect surface
+var protectSurfacePartResources = map[string]struct {
+	resource       func(client *auxo.Client) resource.Resource
+	model          interface{}
+	protectsurface *zerotrust.ProtectSurface
+	removed        func(ps *zerotrust.ProtectSurface) bool
+}{
+	"measures": {
+		resource: func(client *auxo.Client) resource.Resource {
+			return &measureResource{client: client, locks: &keyedMutex{}}
+		},
+		model:          &measureResourceModel{Protectsurface: types.StringValue("ps1")},
+		protectsurface: &zerotrust.ProtectSurface{ID: "ps1", Measures: map[string]zerotrust.MeasureState{"m1": assignedMeasure(true)}},
+		removed: func(ps *zerotrust.ProtectSurface) bool {
+			return len(ps.Measures) == 0
+		},
+	},
+	"transaction flows": {
+		resource: func(client *auxo.Client) resource.Resource {
+			return &transactionflowResource{client: client, locks: &keyedMutex{}}
+		},
+		model: &transactionflowResourceModel{
+			Protectsurface:                 types.StringValue("ps1"),
+			Incoming_protectsurfaces_allow: types.SetNull(types.StringType),
+			Incoming_protectsurfaces_block: types.SetNull(types.StringType),
+			Incoming_protectsurfaces_unset: types.SetNull(types.StringType),
+			Outgoing_protectsurfaces_allow: types.SetNull(types.StringType),
+			Outgoing_protectsurfaces_block: types.SetNull(types.StringType),
+			Outgoing_protectsurfaces_unset: types.SetNull(types.StringType),
+		},
+		protectsurface: &zerotrust.ProtectSurface{
+			ID:               "ps1",
+			FlowsFromOtherPS: map[string]zerotrust.Flow{"ps2": {Allow: boolPtr(true)}},
+			FlowsToOtherPS:   map[string]zerotrust.Flow{"ps3": {Allow: boolPtr(false)}},
+		},
+		removed: func(ps *zerotrust.ProtectSurface) bool {
+			return len(ps.FlowsFromOtherPS) == 0 && len(ps.FlowsToOtherPS) == 0
+		},
+	},
+}
+
+func TestProtectSurfacePartResources(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		// getError is returned on the get of the protect surface, the protect surface does not exist when withPart is false
+		getError *fakeAPIError
+		withPart bool
+		// wantRemoved is true when the resource is removed from the state by Read, wantErr when Read and Delete fail
+		wantRemoved bool
+		wantErr     bool
+		wantGets    int
+		wantUpdates int
+	}{
+		"gone with status 404": {
+			getError:    &fakeAPIError{status: http.StatusNotFound, body: `{"error_id":"410","error_name":"Gone","error_message":"protectsurface deleted"}`},
+			wantRemoved: true,
+			wantGets:    1,
+		},
+		"gone with status 410": {
+			getError:    &fakeAPIError{status: http.StatusGone, body: `{"error_id":"410","error_name":"Gone","error_message":"protectsurface deleted"}`},
+			wantRemoved: true,
+			wantGets:    1,
+		},
+		"not found with status 404": {
+			getError:    &fakeAPIError{status: http.StatusNotFound, body: "404 page not found"},
+			wantRemoved: true,
+			wantGets:    1,
+		},
+		"no items": {
+			wantRemoved: true,
+			wantGets:    1,
+		},
+		"other error": {
+			getError: &fakeAPIError{status: http.StatusInternalServerError, body: `{"error_id":"500","error_name":"Internal server error"}`},
+			wantErr:  true,
+			wantGets: 1,
+		},
+		"part removed": {
+			withPart:    true,
+			wantGets:    2,
+			wantUpdates: 1,
+		},
+	}
+
+	for resourceName, rt := range protectSurfacePartResources {
+		for name, tt := range tests {
+			t.Run(resourceName+"/"+name, func(t *testing.T) {
+				var protectsurfaces []*zerotrust.ProtectSurface
+				if tt.withPart {
+					protectsurfaces = append(protectsurfaces, copyProtectSurface(rt.protectsurface))
+				}
+
+				api, client := newFakeAPI(t, protectsurfaces...)
+				api.getError = tt.getError
+				r := rt.resource(client)
+				state := newTestState(t, r, rt.model)
+
+				if !tt.withPart {
+					readResp := resource.ReadResponse{State: state}
+					r.Read(ctx, resource.ReadRequest{State: state}, &readResp)
+
+					if readResp.Diagnostics.HasError() != tt.wantErr {
+						t.Fatalf("expected error %v on read, got %v", tt.wantErr, readResp.Diagnostics)
+					}
+					if readResp.State.Raw.IsNull() != tt.wantRemoved {
+						t.Errorf("expected removed %v from the state, got %v", tt.wantRemoved, readResp.State.Raw)
+					}
+					api.gets = 0
+				}
+
+				deleteResp := resource.DeleteResponse{State: state}
+				r.Delete(ctx, resource.DeleteRequest{State: state}, &deleteResp)
+
+				if deleteResp.Diagnostics.HasError() != tt.wantErr {
+					t.Fatalf("expected error %v on delete, got %v", tt.wantErr, deleteResp.Diagnostics)
+				}
+				if api.gets != tt.wantGets || api.updates != tt.wantUpdates {
+					t.Errorf("expected %d gets and %d updates on delete, got %d gets and %d updates",
+						tt.wantGets, tt.wantUpdates, api.gets, api.updates)
+				}
+				if tt.withPart && !rt.removed(api.protectsurfaces["ps1"]) {
+					t.Errorf("expected the part to be removed, got %+v", api.protectsurfaces["ps1"])
+				}
+			})
+		}
+	}
+}
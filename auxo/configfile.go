@@ -4,42 +4,60 @@ package auxo
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/mitchellh/go-homedir"
+	"gopkg.in/yaml.v3"
 )
 
 type ConfigFile struct {
-	Configs []ConfigEntry `json:"configs"`
+	Configs []ConfigEntry `json:"configs" yaml:"configs"`
 }
 
 type ConfigEntry struct {
-	Alias       string `json:"alias"`
-	Description string `json:"description"`
-	Token       string `json:"token"`
-	APIAddress  string `json:"apiaddress"`
-	Debug       bool   `json:"debug"`
+	Alias       string `json:"alias" yaml:"alias"`
+	Description string `json:"description" yaml:"description"`
+	Token       string `json:"token" yaml:"token"`
+	APIAddress  string `json:"apiaddress" yaml:"apiaddress"`
+	Debug       bool   `json:"debug" yaml:"debug"`
 }
 
-// Will return the default config file location
-func getDefaultConfigLocation() string {
+// configFileNames are the names of the config file in the default locations, in order of preference
+var configFileNames = []string{"config.json", "config.yaml", "config.yml"}
+
+// Will return the default config file locations, in the order they are searched
+// $XDG_CONFIG_HOME/ztctl (defaults to ~/.config/ztctl) followed by ~/.ztctl
+func getDefaultConfigLocations() ([]string, error) {
 	// Find home directory.
 	home, err := homedir.Dir()
 	if err != nil {
-		log.Fatal(err)
-		os.Exit(1)
+		return nil, fmt.Errorf("unable to determine the home directory: %w", err)
+	}
+
+	xdgConfigHome := os.Getenv("XDG_CONFIG_HOME")
+	if xdgConfigHome == "" {
+		xdgConfigHome = filepath.Join(home, ".config")
 	}
 
-	location := home + "/.ztctl/" + "config.json"
+	var locations []string
+	for _, dir := range []string{filepath.Join(xdgConfigHome, "ztctl"), filepath.Join(home, ".ztctl")} {
+		for _, name := range configFileNames {
+			locations = append(locations, filepath.Join(dir, name))
+		}
+	}
 
-	return location
+	return locations, nil
 }
 
 // GetConfigs will get all config entries from the specified file
-// Specify filename for different location, leave empty for default.
+// JSON and YAML (.yaml or .yml extension) files are supported.
 // returns ConfigFile and error
 func getConfigs(fileName string) (ConfigFile, error) {
 
@@ -50,9 +68,15 @@ func getConfigs(fileName string) (ConfigFile, error) {
 		return ConfigFile{}, err
 	}
 
-	err = json.Unmarshal(cfgFileAsByte, &cfgFile)
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(cfgFileAsByte, &cfgFile)
+	default:
+		err = json.Unmarshal(cfgFileAsByte, &cfgFile)
+	}
+
 	if err != nil {
-		return ConfigFile{}, err
+		return ConfigFile{}, fmt.Errorf("unable to parse %s: %w", fileName, err)
 	}
 
 	return cfgFile, nil
@@ -77,21 +101,43 @@ func readFile(fileName string) ([]byte, error) {
 }
 
 // GetConfig will get the config entry with the specified alias
-// Specify filename for different location, leave empty for default.
+// The files are searched in order, files which do not exist are skipped.
 // returns ConfigEntry and error
-func getConfig(filename, alias string) (ConfigEntry, error) {
-	cfg, err := getConfigs(filename)
+func getConfig(filenames []string, alias string) (ConfigEntry, error) {
+	var searched []string
+	aliases := map[string]bool{}
 
-	if err != nil {
-		return ConfigEntry{}, err
-	}
+	for _, filename := range filenames {
+		cfg, err := getConfigs(filename)
+
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return ConfigEntry{}, err
+		}
+
+		searched = append(searched, filename)
 
-	//Find the config entry with the alias
-	for _, ce := range cfg.Configs {
-		if ce.Alias == alias {
-			return ce, nil
+		//Find the config entry with the alias
+		for _, ce := range cfg.Configs {
+			if ce.Alias == alias {
+				return ce, nil
+			}
+			aliases[ce.Alias] = true
 		}
 	}
 
-	return ConfigEntry{}, fmt.Errorf("Could not find config entry with alias %s", alias)
+	if len(searched) == 0 {
+		return ConfigEntry{}, fmt.Errorf("Could not find a config file, searched [%s]", strings.Join(filenames, ", "))
+	}
+
+	available := make([]string, 0, len(aliases))
+	for a := range aliases {
+		available = append(available, a)
+	}
+	sort.Strings(available)
+
+	return ConfigEntry{}, fmt.Errorf("Could not find config entry with alias %s in [%s], available aliases are [%s]",
+		alias, strings.Join(searched, ", "), strings.Join(available, ", "))
 }
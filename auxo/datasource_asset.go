@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/on2itsecurity/go-auxo/v2"
+	"github.com/on2itsecurity/go-auxo/v2/asset"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -20,8 +21,13 @@ type assetDataSource struct {
 }
 
 type assetDataSourceModel struct {
-	ID   types.String `tfsdk:"id"`
-	Name types.String `tfsdk:"name"`
+	ID              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	TypeName        types.String `tfsdk:"type_name"`
+	TypeDescription types.String `tfsdk:"type_description"`
+	IP              types.String `tfsdk:"ip"`
+	Port            types.String `tfsdk:"port"`
+	Status          types.String `tfsdk:"status"`
 }
 
 // NewassetDataSource is a helper function to simplify the provider implementation.
@@ -45,21 +51,25 @@ func (d *assetDataSource) Configure(_ context.Context, req datasource.ConfigureR
 
 // Schema defines the schema for the data source.
 func (d *assetDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := assetAttributes()
+
+	attributes["id"] = schema.StringAttribute{
+		Description:         "Unique ID of the asset",
+		MarkdownDescription: "Unique ID of the asset",
+		Optional:            true,
+		Computed:            true,
+	}
+	attributes["name"] = schema.StringAttribute{
+		Description:         "Name of the asset",
+		MarkdownDescription: "Name of the asset",
+		Optional:            true,
+		Computed:            true,
+	}
+
 	resp.Schema = schema.Schema{
-		Description:         "A asset which can be used as an `exists_on_asset` in a `state` resource.",
-		MarkdownDescription: "A asset which can be used as an `exists_on_asset` in a `state` resource",
-		Attributes: map[string]schema.Attribute{
-			"id": schema.StringAttribute{
-				Description:         "Computed unique IDs of the asset",
-				MarkdownDescription: "Computed unique IDs of the asset",
-				Computed:            true,
-			},
-			"name": schema.StringAttribute{
-				Description:         "Name of the asset",
-				MarkdownDescription: "Name of the asset",
-				Required:            true,
-			},
-		},
+		Description:         "A asset which can be used as an `exists_on_asset` in a `state` resource. Either by specifying the id or the name",
+		MarkdownDescription: "A asset which can be used as an `exists_on_asset` in a `state` resource. Either by specifying the id or the name",
+		Attributes:          attributes,
 	}
 }
 
@@ -67,6 +77,20 @@ func (d *assetDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 func (d *assetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state assetDataSourceModel
 
+	//Get input
+	var input assetDataSourceModel
+	diags := req.Config.Get(ctx, &input)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	//Check if one of the two is set
+	if (input.ID.IsNull() && input.Name.IsNull()) || (!input.ID.IsNull() && !input.Name.IsNull()) {
+		resp.Diagnostics.AddError("Either id OR name must be set", "")
+		return
+	}
+
 	//Get assets
 	assets, err := d.client.Asset.GetAssets(ctx)
 	if err != nil {
@@ -74,25 +98,30 @@ func (d *assetDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	//Get input
-	var input assetDataSourceModel
-	diags := req.Config.Get(ctx, &input)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
+	//Make map to check for duplicates (which allowed, but makes it impossible to look for an asset by name)
+	assetCount := make(map[string]int)
+	for _, asset := range assets {
+		assetCount[asset.Name]++
+	}
+	if !input.Name.IsNull() && assetCount[input.Name.ValueString()] > 1 {
+		resp.Diagnostics.AddError("Duplicate name on backend, please use id", "Duplicate name on backend name "+input.Name.ValueString()+", please use id")
 		return
 	}
 
 	//Find the asset
 	for _, a := range assets {
-		if a.Name == input.Name.ValueString() {
-			state.ID = types.StringValue(a.ID)
-			state.Name = types.StringValue(a.Name)
+		if (!input.ID.IsNull() && a.ID == input.ID.ValueString()) || (!input.Name.IsNull() && a.Name == input.Name.ValueString()) {
+			state = assetToDataSourceModel(a)
 			break
 		}
 	}
 
 	if state.ID.IsNull() {
-		resp.Diagnostics.AddError("Unable to find asset", "Unable to find asset with name "+input.Name.ValueString())
+		lookup := "id " + input.ID.ValueString()
+		if input.ID.IsNull() {
+			lookup = "name " + input.Name.ValueString()
+		}
+		resp.Diagnostics.AddError("Unable to find asset", "Unable to find asset with "+lookup)
 		return
 	}
 
@@ -103,3 +132,57 @@ func (d *assetDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 }
+
+// assetAttributes returns the (computed) schema attributes of an asset
+func assetAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description:         "Unique ID of the asset",
+			MarkdownDescription: "Unique ID of the asset",
+			Computed:            true,
+		},
+		"name": schema.StringAttribute{
+			Description:         "Name of the asset",
+			MarkdownDescription: "Name of the asset",
+			Computed:            true,
+		},
+		"type_name": schema.StringAttribute{
+			Description:         "Type of the asset",
+			MarkdownDescription: "Type of the asset",
+			Computed:            true,
+		},
+		"type_description": schema.StringAttribute{
+			Description:         "Description of the type of the asset",
+			MarkdownDescription: "Description of the type of the asset",
+			Computed:            true,
+		},
+		"ip": schema.StringAttribute{
+			Description:         "IP address of the asset",
+			MarkdownDescription: "IP address of the asset",
+			Computed:            true,
+		},
+		"port": schema.StringAttribute{
+			Description:         "Port of the asset",
+			MarkdownDescription: "Port of the asset",
+			Computed:            true,
+		},
+		"status": schema.StringAttribute{
+			Description:         "Status of the asset",
+			MarkdownDescription: "Status of the asset",
+			Computed:            true,
+		},
+	}
+}
+
+// assetToDataSourceModel maps the asset.AssetItem object to the data source model
+func assetToDataSourceModel(a *asset.AssetItem) assetDataSourceModel {
+	return assetDataSourceModel{
+		ID:              types.StringValue(a.ID),
+		Name:            types.StringValue(a.Name),
+		TypeName:        types.StringValue(a.TypeName),
+		TypeDescription: types.StringValue(a.TypeDescription),
+		IP:              types.StringValue(a.IP),
+		Port:            types.StringValue(a.Port),
+		Status:          types.StringValue(a.Status),
+	}
+}
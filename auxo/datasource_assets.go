@@ -0,0 +1,139 @@
+package auxo
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/on2itsecurity/go-auxo/v2"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &assetsDataSource{}
+	_ datasource.DataSourceWithConfigure = &assetsDataSource{}
+)
+
+type assetsDataSource struct {
+	client *auxo.Client
+}
+
+type assetsDataSourceModel struct {
+	NameRegex types.String           `tfsdk:"name_regex"`
+	TypeName  types.String           `tfsdk:"type_name"`
+	Status    types.String           `tfsdk:"status"`
+	IDs       types.List             `tfsdk:"ids"`
+	Assets    []assetDataSourceModel `tfsdk:"assets"`
+}
+
+// NewAssetsDataSource is a helper function to simplify the provider implementation.
+func NewAssetsDataSource() datasource.DataSource {
+	return &assetsDataSource{}
+}
+
+// Metadata returns the data source type name.
+func (d *assetsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_assets"
+}
+
+func (d *assetsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	// Retrieve the client from the provider config
+	d.client = req.ProviderData.(*auxo.Client)
+}
+
+// Schema defines the schema for the data source.
+func (d *assetsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "All assets, optionally filtered, which can be used as `exists_on_assets` in a `state` resource.",
+		MarkdownDescription: "All assets, optionally filtered, which can be used as `exists_on_assets` in a `state` resource.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Description:         "Only return assets of which the name matches this regular expression",
+				MarkdownDescription: "Only return assets of which the name matches this regular expression",
+				Optional:            true,
+			},
+			"type_name": schema.StringAttribute{
+				Description:         "Only return assets of this type",
+				MarkdownDescription: "Only return assets of this type",
+				Optional:            true,
+			},
+			"status": schema.StringAttribute{
+				Description:         "Only return assets with this status",
+				MarkdownDescription: "Only return assets with this status",
+				Optional:            true,
+			},
+			"ids": schema.ListAttribute{
+				Description:         "The IDs of the matching assets",
+				MarkdownDescription: "The IDs of the matching assets",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"assets": schema.ListNestedAttribute{
+				Description:         "The matching assets",
+				MarkdownDescription: "The matching assets",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: assetAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *assetsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	//Get input
+	var state assetsDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nameRegex, err := regexp.Compile(state.NameRegex.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid name_regex", err.Error())
+		return
+	}
+
+	//Get assets
+	assets, err := d.client.Asset.GetAssets(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to retrieve assets", err.Error())
+		return
+	}
+
+	//Filter the assets
+	ids := []string{}
+	state.Assets = []assetDataSourceModel{}
+	for _, a := range assets {
+		if !nameRegex.MatchString(a.Name) {
+			continue
+		}
+		if !state.TypeName.IsNull() && a.TypeName != state.TypeName.ValueString() {
+			continue
+		}
+		if !state.Status.IsNull() && a.Status != state.Status.ValueString() {
+			continue
+		}
+
+		ids = append(ids, a.ID)
+		state.Assets = append(state.Assets, assetToDataSourceModel(a))
+	}
+
+	state.IDs, diags = types.ListValueFrom(ctx, types.StringType, ids)
+	resp.Diagnostics.Append(diags...)
+
+	//set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -2,11 +2,14 @@ package auxo
 
 import (
 	"context"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/on2itsecurity/go-auxo/v2"
+	"github.com/on2itsecurity/go-auxo/v2/crm"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -20,8 +23,21 @@ type contactDataSource struct {
 }
 
 type contactDataSourceModel struct {
-	ID    types.String `tfsdk:"id"`
-	Email types.String `tfsdk:"email"`
+	ID                  types.String `tfsdk:"id"`
+	Email               types.String `tfsdk:"email"`
+	EmailAliases        types.List   `tfsdk:"email_aliases"`
+	FullName            types.String `tfsdk:"full_name"`
+	FirstName           types.String `tfsdk:"first_name"`
+	LastName            types.String `tfsdk:"last_name"`
+	Initials            types.String `tfsdk:"initials"`
+	Particles           types.String `tfsdk:"particles"`
+	Title               types.String `tfsdk:"title"`
+	Salutation          types.String `tfsdk:"salutation"`
+	Gender              types.String `tfsdk:"gender"`
+	MobilePhone         types.String `tfsdk:"mobile_phone"`
+	LocaleLang          types.String `tfsdk:"locale_lang"`
+	Status              types.String `tfsdk:"status"`
+	IsShownTicketsurvey types.Bool   `tfsdk:"is_shown_ticketsurvey"`
 }
 
 // NewcontactDataSource is a helper function to simplify the provider implementation.
@@ -45,27 +61,55 @@ func (d *contactDataSource) Configure(_ context.Context, req datasource.Configur
 
 // Schema defines the schema for the data source.
 func (d *contactDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := contactAttributes()
+
+	attributes["id"] = schema.StringAttribute{
+		Description:         "Unique ID of the contact",
+		MarkdownDescription: "Unique ID of the contact",
+		Optional:            true,
+		Computed:            true,
+	}
+	attributes["email"] = schema.StringAttribute{
+		Description:         "Email of the contact, matched case-insensitive on the email and email aliases",
+		MarkdownDescription: "Email of the contact, matched case-insensitive on the email and email aliases",
+		Optional:            true,
+		Computed:            true,
+	}
+	attributes["full_name"] = schema.StringAttribute{
+		Description:         "Full name of the contact",
+		MarkdownDescription: "Full name of the contact",
+		Optional:            true,
+		Computed:            true,
+	}
+
 	resp.Schema = schema.Schema{
-		Description:         "A contact which can be used a.o. as main- or securitycontact in a `protectsurface`.",
-		MarkdownDescription: "A contact which can be used a.o. as main- or securitycontact in a `protectsurface`.",
-		Attributes: map[string]schema.Attribute{
-			"id": schema.StringAttribute{
-				Description:         "Computed unique IDs of the contact",
-				MarkdownDescription: "Computed unique IDs of the contact",
-				Computed:            true,
-			},
-			"email": schema.StringAttribute{
-				Description:         "Emails of the contact",
-				MarkdownDescription: "Emails of the contact",
-				Required:            true,
-			},
-		},
+		Description:         "A contact which can be used a.o. as main- or securitycontact in a `protectsurface`. Either by specifying the id, email or full_name",
+		MarkdownDescription: "A contact which can be used a.o. as main- or securitycontact in a `protectsurface`. Either by specifying the id, email or full_name",
+		Attributes:          attributes,
 	}
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *contactDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	var state contactDataSourceModel
+	//Get input
+	var input contactDataSourceModel
+	diags := req.Config.Get(ctx, &input)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	//Check if exactly one of the lookup attributes is set
+	lookups := 0
+	for _, v := range []types.String{input.ID, input.Email, input.FullName} {
+		if !v.IsNull() {
+			lookups++
+		}
+	}
+	if lookups != 1 {
+		resp.Diagnostics.AddError("Either id, email OR full_name must be set", "")
+		return
+	}
 
 	//Get contacts
 	contacts, err := d.client.CRM.GetContacts(ctx)
@@ -74,26 +118,49 @@ func (d *contactDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 
-	//Get input
-	var input contactDataSourceModel
-	diags := req.Config.Get(ctx, &input)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
+	//Find the contact(s)
+	var matches []*crm.Contact
+	var lookup string
+	switch {
+	case !input.ID.IsNull():
+		lookup = "id " + input.ID.ValueString()
+		for _, c := range contacts {
+			if c.ID == input.ID.ValueString() {
+				matches = append(matches, c)
+			}
+		}
+	case !input.Email.IsNull():
+		lookup = "email " + input.Email.ValueString()
+		matches = findContactsByEmail(contacts, input.Email.ValueString())
+	default:
+		lookup = "full_name " + input.FullName.ValueString()
+		for _, c := range contacts {
+			if c.FullName == input.FullName.ValueString() {
+				matches = append(matches, c)
+			}
+		}
+	}
+
+	if len(matches) == 0 {
+		resp.Diagnostics.AddError("Unable to find contact", "Unable to find contact with "+lookup)
 		return
 	}
 
-	//Find the contact
-	for _, c := range contacts {
-		if c.Email == input.Email.ValueString() {
-			state.ID = types.StringValue(c.ID)
-			state.Email = types.StringValue(c.Email)
-			break
+	if len(matches) > 1 {
+		var ids []string
+		for _, c := range matches {
+			ids = append(ids, c.ID)
 		}
+		resp.Diagnostics.AddError("Multiple contacts found", "Multiple contacts found with "+lookup+" ["+strings.Join(ids, ",")+"], please use the id")
+		return
 	}
 
-	if state.ID.IsNull() {
-		resp.Diagnostics.AddError("Unable to find contact", "Unable to find contact with email "+input.Email.ValueString())
-		return
+	state, diags := contactToDataSourceModel(ctx, matches[0])
+	resp.Diagnostics.Append(diags...)
+
+	//Keep the lookup value as configured (email is matched case-insensitive)
+	if !input.Email.IsNull() {
+		state.Email = input.Email
 	}
 
 	//set state
@@ -103,3 +170,130 @@ func (d *contactDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 }
+
+// contactAttributes returns the (computed) schema attributes of a contact
+func contactAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description:         "Unique ID of the contact",
+			MarkdownDescription: "Unique ID of the contact",
+			Computed:            true,
+		},
+		"email": schema.StringAttribute{
+			Description:         "Email of the contact",
+			MarkdownDescription: "Email of the contact",
+			Computed:            true,
+		},
+		"email_aliases": schema.ListAttribute{
+			Description:         "Email aliases of the contact",
+			MarkdownDescription: "Email aliases of the contact",
+			Computed:            true,
+			ElementType:         types.StringType,
+		},
+		"full_name": schema.StringAttribute{
+			Description:         "Full name of the contact",
+			MarkdownDescription: "Full name of the contact",
+			Computed:            true,
+		},
+		"first_name": schema.StringAttribute{
+			Description:         "First name of the contact",
+			MarkdownDescription: "First name of the contact",
+			Computed:            true,
+		},
+		"last_name": schema.StringAttribute{
+			Description:         "Last name of the contact",
+			MarkdownDescription: "Last name of the contact",
+			Computed:            true,
+		},
+		"initials": schema.StringAttribute{
+			Description:         "Initials of the contact",
+			MarkdownDescription: "Initials of the contact",
+			Computed:            true,
+		},
+		"particles": schema.StringAttribute{
+			Description:         "Particles of the name of the contact",
+			MarkdownDescription: "Particles of the name of the contact",
+			Computed:            true,
+		},
+		"title": schema.StringAttribute{
+			Description:         "Title of the contact (e.g. Mr, Mrs, Dr)",
+			MarkdownDescription: "Title of the contact (e.g. Mr, Mrs, Dr)",
+			Computed:            true,
+		},
+		"salutation": schema.StringAttribute{
+			Description:         "Salutation used in communications with the contact",
+			MarkdownDescription: "Salutation used in communications with the contact",
+			Computed:            true,
+		},
+		"gender": schema.StringAttribute{
+			Description:         "Gender of the contact",
+			MarkdownDescription: "Gender of the contact",
+			Computed:            true,
+		},
+		"mobile_phone": schema.StringAttribute{
+			Description:         "Mobile phone number of the contact",
+			MarkdownDescription: "Mobile phone number of the contact",
+			Computed:            true,
+		},
+		"locale_lang": schema.StringAttribute{
+			Description:         "Language of the contact",
+			MarkdownDescription: "Language of the contact",
+			Computed:            true,
+		},
+		"status": schema.StringAttribute{
+			Description:         "Status of the contact (e.g. normal, inactive)",
+			MarkdownDescription: "Status of the contact (e.g. normal, inactive)",
+			Computed:            true,
+		},
+		"is_shown_ticketsurvey": schema.BoolAttribute{
+			Description:         "Is the contact participating in the ticket survey",
+			MarkdownDescription: "Is the contact participating in the ticket survey",
+			Computed:            true,
+		},
+	}
+}
+
+// contactToDataSourceModel maps the crm.Contact object to the data source model
+func contactToDataSourceModel(ctx context.Context, c *crm.Contact) (contactDataSourceModel, diag.Diagnostics) {
+	emailAliases := c.EmailAliases
+	if emailAliases == nil {
+		emailAliases = []string{}
+	}
+	aliases, diags := types.ListValueFrom(ctx, types.StringType, emailAliases)
+
+	return contactDataSourceModel{
+		ID:                  types.StringValue(c.ID),
+		Email:               types.StringValue(c.Email),
+		EmailAliases:        aliases,
+		FullName:            types.StringValue(c.FullName),
+		FirstName:           types.StringValue(c.FirstName),
+		LastName:            types.StringValue(c.LastName),
+		Initials:            types.StringValue(c.Initials),
+		Particles:           types.StringValue(c.Particles),
+		Title:               types.StringValue(c.Title),
+		Salutation:          types.StringValue(c.Salutation),
+		Gender:              types.StringValue(c.Gender),
+		MobilePhone:         types.StringValue(c.MobilePhone),
+		LocaleLang:          types.StringValue(c.LocaleLang),
+		Status:              types.StringValue(c.Status),
+		IsShownTicketsurvey: types.BoolValue(c.IsShownTicketsurvey),
+	}, diags
+}
+
+// findContactsByEmail returns the contacts of which the email, or one of the email aliases, matches (case-insensitive)
+func findContactsByEmail(contacts []*crm.Contact, email string) []*crm.Contact {
+	var result []*crm.Contact
+	for _, c := range contacts {
+		if strings.EqualFold(c.Email, email) {
+			result = append(result, c)
+			continue
+		}
+		for _, alias := range c.EmailAliases {
+			if strings.EqualFold(alias, email) {
+				result = append(result, c)
+				break
+			}
+		}
+	}
+	return result
+}
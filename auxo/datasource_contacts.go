@@ -0,0 +1,132 @@
+package auxo
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/on2itsecurity/go-auxo/v2"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &contactsDataSource{}
+	_ datasource.DataSourceWithConfigure = &contactsDataSource{}
+)
+
+type contactsDataSource struct {
+	client *auxo.Client
+}
+
+type contactsDataSourceModel struct {
+	EmailRegex    types.String             `tfsdk:"email_regex"`
+	FullNameRegex types.String             `tfsdk:"full_name_regex"`
+	Status        types.String             `tfsdk:"status"`
+	Contacts      []contactDataSourceModel `tfsdk:"contacts"`
+}
+
+// NewContactsDataSource is a helper function to simplify the provider implementation.
+func NewContactsDataSource() datasource.DataSource {
+	return &contactsDataSource{}
+}
+
+// Metadata returns the data source type name.
+func (d *contactsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_contacts"
+}
+
+func (d *contactsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	// Retrieve the client from the provider config
+	d.client = req.ProviderData.(*auxo.Client)
+}
+
+// Schema defines the schema for the data source.
+func (d *contactsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "All contacts, optionally filtered, which can be used a.o. as main- or securitycontact in a `protectsurface`.",
+		MarkdownDescription: "All contacts, optionally filtered, which can be used a.o. as main- or securitycontact in a `protectsurface`.",
+		Attributes: map[string]schema.Attribute{
+			"email_regex": schema.StringAttribute{
+				Description:         "Only return contacts of which the email matches this regular expression",
+				MarkdownDescription: "Only return contacts of which the email matches this regular expression",
+				Optional:            true,
+			},
+			"full_name_regex": schema.StringAttribute{
+				Description:         "Only return contacts of which the full name matches this regular expression",
+				MarkdownDescription: "Only return contacts of which the full name matches this regular expression",
+				Optional:            true,
+			},
+			"status": schema.StringAttribute{
+				Description:         "Only return contacts with this status (e.g. normal, inactive)",
+				MarkdownDescription: "Only return contacts with this status (e.g. normal, inactive)",
+				Optional:            true,
+			},
+			"contacts": schema.ListNestedAttribute{
+				Description:         "The matching contacts",
+				MarkdownDescription: "The matching contacts",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: contactAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *contactsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	//Get input
+	var state contactsDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	emailRegex, err := regexp.Compile(state.EmailRegex.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid email_regex", err.Error())
+		return
+	}
+
+	fullNameRegex, err := regexp.Compile(state.FullNameRegex.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid full_name_regex", err.Error())
+		return
+	}
+
+	//Get contacts
+	contacts, err := d.client.CRM.GetContacts(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to retrieve contacts", err.Error())
+		return
+	}
+
+	//Filter the contacts
+	state.Contacts = []contactDataSourceModel{}
+	for _, c := range contacts {
+		if !emailRegex.MatchString(c.Email) || !fullNameRegex.MatchString(c.FullName) {
+			continue
+		}
+		if !state.Status.IsNull() && c.Status != state.Status.ValueString() {
+			continue
+		}
+
+		contact, diags := contactToDataSourceModel(ctx, c)
+		resp.Diagnostics.Append(diags...)
+		state.Contacts = append(state.Contacts, contact)
+	}
+
+	//set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
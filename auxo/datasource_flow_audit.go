@@ -0,0 +1,236 @@
+package auxo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/on2itsecurity/go-auxo/v2"
+	"github.com/on2itsecurity/go-auxo/v2/zerotrust"
+)
+
+// Kinds of flow audit findings
+const (
+	findingAsymmetric    = "asymmetric"
+	findingContradictory = "contradictory"
+	findingDangling      = "dangling"
+	findingOutside       = "outside"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &flowAuditDataSource{}
+	_ datasource.DataSourceWithConfigure = &flowAuditDataSource{}
+)
+
+type flowAuditDataSource struct {
+	client *auxo.Client
+}
+
+type flowAuditDataSourceModel struct {
+	Findings []flowAuditFindingModel `tfsdk:"findings"`
+}
+
+type flowAuditFindingModel struct {
+	Kind           types.String `tfsdk:"kind"`
+	Protectsurface types.String `tfsdk:"protectsurface"`
+	From           types.String `tfsdk:"from"`
+	To             types.String `tfsdk:"to"`
+	Outgoing       types.String `tfsdk:"outgoing"`
+	Incoming       types.String `tfsdk:"incoming"`
+	Message        types.String `tfsdk:"message"`
+}
+
+// flowFinding is an inconsistency in the flows, protectsurface is the protect surface on which it should be fixed
+type flowFinding struct {
+	kind           string
+	protectsurface string
+	flow           flowMatrixEntry
+	message        string
+}
+
+// NewFlowAuditDataSource is a helper function to simplify the provider implementation.
+func NewFlowAuditDataSource() datasource.DataSource {
+	return &flowAuditDataSource{}
+}
+
+// Metadata returns the data source type name.
+func (d *flowAuditDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_flow_audit"
+}
+
+func (d *flowAuditDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	// Retrieve the client from the provider config
+	d.client = req.ProviderData.(*auxo.Client)
+}
+
+// Schema defines the schema for the data source.
+func (d *flowAuditDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Audit of the flows between all protect surfaces, which reports asymmetric, contradictory and dangling flows and flows which conflict with the flows from or to outside.",
+		MarkdownDescription: "Audit of the flows between all protect surfaces, which reports asymmetric, contradictory and dangling flows and flows which conflict with the flows from or to outside.",
+		Attributes: map[string]schema.Attribute{
+			"findings": schema.ListNestedAttribute{
+				Description:         "The inconsistent flows, ordered by kind, from and to",
+				MarkdownDescription: "The inconsistent flows, ordered by `kind`, `from` and `to`",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"kind": schema.StringAttribute{
+							Description:         "asymmetric (allowed on one side, not defined or unset on the other side), contradictory (allowed on one side, blocked on the other side), dangling (to or from a protectsurface which does not exist) or outside (allowed from or to a protectsurface outside the control boundary, while flows from or to outside are not allowed)",
+							MarkdownDescription: "`asymmetric` (allowed on one side, not defined or unset on the other side), `contradictory` (allowed on one side, blocked on the other side), `dangling` (to or from a protectsurface which does not exist) or `outside` (allowed from or to a protectsurface outside the control boundary, while `allow_flows_from_outside` or `allow_flows_to_outside` is not set)",
+							Computed:            true,
+						},
+						"protectsurface": schema.StringAttribute{
+							Description:         "The ID of the protectsurface on which the flow should be fixed",
+							MarkdownDescription: "The ID of the protectsurface on which the flow should be fixed",
+							Computed:            true,
+						},
+						"from": schema.StringAttribute{
+							Description:         "The ID of the protectsurface the flow comes from",
+							MarkdownDescription: "The ID of the protectsurface the flow comes from",
+							Computed:            true,
+						},
+						"to": schema.StringAttribute{
+							Description:         "The ID of the protectsurface the flow goes to",
+							MarkdownDescription: "The ID of the protectsurface the flow goes to",
+							Computed:            true,
+						},
+						"outgoing": schema.StringAttribute{
+							Description:         "The outgoing flow on the from protectsurface, allow, block, unset or none (not defined)",
+							MarkdownDescription: "The outgoing flow on the `from` protectsurface, `allow`, `block`, `unset` or `none` (not defined)",
+							Computed:            true,
+						},
+						"incoming": schema.StringAttribute{
+							Description:         "The incoming flow on the to protectsurface, allow, block, unset or none (not defined)",
+							MarkdownDescription: "The incoming flow on the `to` protectsurface, `allow`, `block`, `unset` or `none` (not defined)",
+							Computed:            true,
+						},
+						"message": schema.StringAttribute{
+							Description:         "Description of the finding",
+							MarkdownDescription: "Description of the finding",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *flowAuditDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state flowAuditDataSourceModel
+
+	//Get protectsurfaces
+	protectsurfaces, err := d.client.ZeroTrust.GetProtectSurfaces(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to retrieve protectsurfaces", err.Error())
+		return
+	}
+
+	state.Findings = []flowAuditFindingModel{}
+	for _, f := range auditFlows(protectsurfaces) {
+		state.Findings = append(state.Findings, flowAuditFindingModel{
+			Kind:           types.StringValue(f.kind),
+			Protectsurface: types.StringValue(f.protectsurface),
+			From:           types.StringValue(f.flow.from),
+			To:             types.StringValue(f.flow.to),
+			Outgoing:       types.StringValue(f.flow.outgoing),
+			Incoming:       types.StringValue(f.flow.incoming),
+			Message:        types.StringValue(f.message),
+		})
+	}
+
+	//set state
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// auditFlows compares the outgoing and incoming flows of all protect surfaces and returns the inconsistencies,
+// ordered by kind, from, to and the protect surface on which it should be fixed
+func auditFlows(protectsurfaces []*zerotrust.ProtectSurface) []flowFinding {
+	known := make(map[string]*zerotrust.ProtectSurface, len(protectsurfaces))
+	for _, ps := range protectsurfaces {
+		known[ps.ID] = ps
+	}
+
+	label := func(id string) string {
+		if ps, ok := known[id]; ok {
+			return ps.Name + " (" + id + ")"
+		}
+		return id
+	}
+
+	var findings []flowFinding
+	for _, e := range buildFlowMatrix(protectsurfaces) {
+		from, to := known[e.from], known[e.to]
+
+		//A flow to or from a deleted protect surface, the other checks need both protect surfaces
+		if from == nil || to == nil {
+			owner, missing := e.from, e.to
+			if from == nil {
+				owner, missing = e.to, e.from
+			}
+			findings = append(findings, flowFinding{findingDangling, owner, e,
+				fmt.Sprintf("Protectsurface %s has a flow with protectsurface %s, which does not exist", label(owner), missing)})
+			continue
+		}
+
+		switch {
+		case e.outgoing == flowAllow && e.incoming == flowBlock:
+			findings = append(findings, flowFinding{findingContradictory, e.to, e,
+				fmt.Sprintf("Protectsurface %s allows the flow to %s, which blocks it", label(e.from), label(e.to))})
+		case e.outgoing == flowBlock && e.incoming == flowAllow:
+			findings = append(findings, flowFinding{findingContradictory, e.from, e,
+				fmt.Sprintf("Protectsurface %s allows the flow from %s, which blocks it", label(e.to), label(e.from))})
+		case e.outgoing == flowAllow && e.incoming != flowAllow:
+			findings = append(findings, flowFinding{findingAsymmetric, e.to, e,
+				fmt.Sprintf("Protectsurface %s allows the flow to %s, which does not allow it (%s)", label(e.from), label(e.to), e.incoming)})
+		case e.incoming == flowAllow && e.outgoing != flowAllow:
+			findings = append(findings, flowFinding{findingAsymmetric, e.from, e,
+				fmt.Sprintf("Protectsurface %s allows the flow from %s, which does not allow it (%s)", label(e.to), label(e.from), e.outgoing)})
+		}
+
+		//Allowed flows with a protect surface outside the control boundary are flows from or to outside
+		if e.incoming == flowAllow && !from.InControlBoundary && !isAllowed(to.FlowsFromOutside) {
+			findings = append(findings, flowFinding{findingOutside, e.to, e,
+				fmt.Sprintf("Protectsurface %s allows the flow from %s, which is outside the control boundary, but does not allow flows from outside", label(e.to), label(e.from))})
+		}
+		if e.outgoing == flowAllow && !to.InControlBoundary && !isAllowed(from.FlowsToOutside) {
+			findings = append(findings, flowFinding{findingOutside, e.from, e,
+				fmt.Sprintf("Protectsurface %s allows the flow to %s, which is outside the control boundary, but does not allow flows to outside", label(e.from), label(e.to))})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		switch {
+		case a.kind != b.kind:
+			return a.kind < b.kind
+		case a.flow.from != b.flow.from:
+			return a.flow.from < b.flow.from
+		case a.flow.to != b.flow.to:
+			return a.flow.to < b.flow.to
+		default:
+			return a.protectsurface < b.protectsurface
+		}
+	})
+
+	return findings
+}
+
+// isAllowed returns true when the flow is allowed, a flow without allow is not allowed
+func isAllowed(flow zerotrust.Flow) bool {
+	return flow.Allow != nil && *flow.Allow
+}
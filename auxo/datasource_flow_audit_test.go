@@ -0,0 +1,86 @@
+package auxo
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/on2itsecurity/go-auxo/v2/zerotrust"
+)
+
+func TestAuditFlows(t *testing.T) {
+	allow, block := zerotrust.Flow{Allow: boolPtr(true)}, zerotrust.Flow{Allow: boolPtr(false)}
+
+	// newPS returns a protect surface in the control boundary, with the outgoing and incoming flows
+	newPS := func(id string, to, from map[string]zerotrust.Flow) *zerotrust.ProtectSurface {
+		return &zerotrust.ProtectSurface{ID: id, Name: "ps " + id, InControlBoundary: true, FlowsToOtherPS: to, FlowsFromOtherPS: from}
+	}
+
+	// finding is the kind, protect surface, from and to of a finding
+	type finding [4]string
+
+	tests := map[string]struct {
+		protectsurfaces []*zerotrust.ProtectSurface
+		want            []finding
+	}{
+		"consistent": {
+			protectsurfaces: []*zerotrust.ProtectSurface{
+				newPS("ps1", map[string]zerotrust.Flow{"ps2": allow}, nil),
+				newPS("ps2", nil, map[string]zerotrust.Flow{"ps1": allow}),
+			},
+		},
+		"asymmetric": {
+			protectsurfaces: []*zerotrust.ProtectSurface{
+				newPS("ps1", map[string]zerotrust.Flow{"ps2": allow}, nil),
+				newPS("ps2", nil, nil),
+			},
+			want: []finding{{findingAsymmetric, "ps2", "ps1", "ps2"}},
+		},
+		"contradictory": {
+			protectsurfaces: []*zerotrust.ProtectSurface{
+				newPS("ps1", map[string]zerotrust.Flow{"ps2": block}, nil),
+				newPS("ps2", nil, map[string]zerotrust.Flow{"ps1": allow}),
+			},
+			want: []finding{{findingContradictory, "ps1", "ps1", "ps2"}},
+		},
+		"dangling": {
+			protectsurfaces: []*zerotrust.ProtectSurface{
+				newPS("ps1", nil, map[string]zerotrust.Flow{"ps9": allow}),
+			},
+			want: []finding{{findingDangling, "ps1", "ps9", "ps1"}},
+		},
+		"outside": {
+			protectsurfaces: []*zerotrust.ProtectSurface{
+				newPS("ps1", nil, map[string]zerotrust.Flow{"ps3": allow}),
+				{ID: "ps3", Name: "ps ps3", FlowsToOtherPS: map[string]zerotrust.Flow{"ps1": allow}},
+			},
+			want: []finding{{findingOutside, "ps1", "ps3", "ps1"}},
+		},
+		"ordered by kind, from and to": {
+			protectsurfaces: []*zerotrust.ProtectSurface{
+				newPS("ps3", map[string]zerotrust.Flow{"ps2": allow}, nil),
+				newPS("ps2", map[string]zerotrust.Flow{"ps1": allow}, map[string]zerotrust.Flow{"ps3": block}),
+				newPS("ps1", map[string]zerotrust.Flow{"ps2": allow, "ps9": allow, "ps8": block}, nil),
+			},
+			want: []finding{
+				{findingAsymmetric, "ps2", "ps1", "ps2"},
+				{findingAsymmetric, "ps1", "ps2", "ps1"},
+				{findingContradictory, "ps2", "ps3", "ps2"},
+				{findingDangling, "ps1", "ps1", "ps8"},
+				{findingDangling, "ps1", "ps1", "ps9"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []finding
+			for _, f := range auditFlows(tt.protectsurfaces) {
+				got = append(got, finding{f.kind, f.protectsurface, f.flow.from, f.flow.to})
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
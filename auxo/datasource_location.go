@@ -49,12 +49,13 @@ func (d *locationDataSource) Configure(_ context.Context, req datasource.Configu
 // Schema defines the schema for the data source.
 func (d *locationDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description:         "A location which can be used in a state object to reflect the location of the resources. Either by specifying the name or the uniqueness_key",
-		MarkdownDescription: "A location which can be used in a state object to reflect the location of the resources. Either by specifying the name or the uniqueness_key",
+		Description:         "A location which can be used in a state object to reflect the location of the resources. Either by specifying the id, name or the uniqueness_key",
+		MarkdownDescription: "A location which can be used in a state object to reflect the location of the resources. Either by specifying the id, name or the uniqueness_key",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				Description:         "Computed unique IDs of the location",
-				MarkdownDescription: "Computed unique IDs of the location",
+				Description:         "Unique ID of the location",
+				MarkdownDescription: "Unique ID of the location",
+				Optional:            true,
 				Computed:            true,
 			},
 			"uniqueness_key": schema.StringAttribute{
@@ -105,20 +106,28 @@ func (d *locationDataSource) Read(ctx context.Context, req datasource.ReadReques
 	for _, location := range locations {
 		locCount[location.Name]++
 	}
-	if locCount[input.Name.ValueString()] > 1 {
+	if !input.Name.IsNull() && locCount[input.Name.ValueString()] > 1 {
 		resp.Diagnostics.AddError("Duplicate name on backend, please use uniqueness key", "Duplicate name on backend name "+input.Name.ValueString()+", please use uniqueness key")
 		return
 	}
 
-	//Check if one of the two is set
-	if (input.Uniqueness_key.IsNull() && input.Name.IsNull()) || (!input.Uniqueness_key.IsNull() && !input.Name.IsNull()) {
-		resp.Diagnostics.AddError("Either uniqueness_key OR name must be set", "")
+	//Check if exactly one of the three is set
+	lookups := 0
+	for _, v := range []types.String{input.ID, input.Uniqueness_key, input.Name} {
+		if !v.IsNull() {
+			lookups++
+		}
+	}
+	if lookups != 1 {
+		resp.Diagnostics.AddError("Either id, uniqueness_key OR name must be set", "")
 		return
 	}
 
 	//Find the location
 	for _, l := range locations {
-		if (l.UniquenessKey == input.Uniqueness_key.ValueString()) || (l.Name == input.Name.ValueString()) {
+		if (!input.ID.IsNull() && l.ID == input.ID.ValueString()) ||
+			(!input.Uniqueness_key.IsNull() && l.UniquenessKey == input.Uniqueness_key.ValueString()) ||
+			(!input.Name.IsNull() && l.Name == input.Name.ValueString()) {
 			state.ID = types.StringValue(l.ID)
 			state.Name = types.StringValue(l.Name)
 			state.Uniqueness_key = types.StringValue(l.UniquenessKey)
@@ -129,7 +138,13 @@ func (d *locationDataSource) Read(ctx context.Context, req datasource.ReadReques
 	}
 
 	if state.ID.IsNull() {
-		resp.Diagnostics.AddError("Unable to find location", "Unable to find location with uniqueness_key "+input.Uniqueness_key.ValueString()+" or name "+input.Name.ValueString())
+		lookup := "name " + input.Name.ValueString()
+		if !input.ID.IsNull() {
+			lookup = "id " + input.ID.ValueString()
+		} else if !input.Uniqueness_key.IsNull() {
+			lookup = "uniqueness_key " + input.Uniqueness_key.ValueString()
+		}
+		resp.Diagnostics.AddError("Unable to find location", "Unable to find location with "+lookup)
 		return
 	}
 
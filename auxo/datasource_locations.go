@@ -0,0 +1,254 @@
+package auxo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/on2itsecurity/go-auxo/v2"
+)
+
+// earthRadiusKm is the mean radius of the earth, used for the haversine distance
+const earthRadiusKm = 6371.0
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource                   = &locationsDataSource{}
+	_ datasource.DataSourceWithConfigure      = &locationsDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &locationsDataSource{}
+)
+
+type locationsDataSource struct {
+	client *auxo.Client
+}
+
+type locationsDataSourceModel struct {
+	NameRegex types.String             `tfsdk:"name_regex"`
+	Near      *locationsNearModel      `tfsdk:"near"`
+	Locations []locationsLocationModel `tfsdk:"locations"`
+}
+
+type locationsNearModel struct {
+	Latitude  types.Float64 `tfsdk:"latitude"`
+	Longitude types.Float64 `tfsdk:"longitude"`
+	RadiusKm  types.Float64 `tfsdk:"radius_km"`
+}
+
+type locationsLocationModel struct {
+	ID             types.String  `tfsdk:"id"`
+	Uniqueness_key types.String  `tfsdk:"uniqueness_key"`
+	Name           types.String  `tfsdk:"name"`
+	Latitude       types.Float64 `tfsdk:"latitude"`
+	Longitude      types.Float64 `tfsdk:"longitude"`
+	DistanceKm     types.Float64 `tfsdk:"distance_km"`
+}
+
+// NewLocationsDataSource is a helper function to simplify the provider implementation.
+func NewLocationsDataSource() datasource.DataSource {
+	return &locationsDataSource{}
+}
+
+// Metadata returns the data source type name.
+func (d *locationsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_locations"
+}
+
+func (d *locationsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	// Retrieve the client from the provider config
+	d.client = req.ProviderData.(*auxo.Client)
+}
+
+// Schema defines the schema for the data source.
+func (d *locationsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "All locations, optionally filtered by name or by distance to a coordinate, which can be used in a state object to reflect the location of the resources.",
+		MarkdownDescription: "All locations, optionally filtered by name or by distance to a coordinate, which can be used in a state object to reflect the location of the resources.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Description:         "Only return locations of which the name matches this regular expression",
+				MarkdownDescription: "Only return locations of which the name matches this regular expression",
+				Optional:            true,
+			},
+			"near": schema.SingleNestedAttribute{
+				Description:         "Only return locations within the radius of this coordinate, ordered by distance (nearest first)",
+				MarkdownDescription: "Only return locations within the radius of this coordinate, ordered by distance (nearest first)",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"latitude": schema.Float64Attribute{
+						Description:         "Latitude of the coordinate, between -90 and 90",
+						MarkdownDescription: "Latitude of the coordinate, between -90 and 90",
+						Required:            true,
+					},
+					"longitude": schema.Float64Attribute{
+						Description:         "Longitude of the coordinate, between -180 and 180",
+						MarkdownDescription: "Longitude of the coordinate, between -180 and 180",
+						Required:            true,
+					},
+					"radius_km": schema.Float64Attribute{
+						Description:         "Radius around the coordinate in kilometers, zero or more",
+						MarkdownDescription: "Radius around the coordinate in kilometers, zero or more",
+						Required:            true,
+					},
+				},
+			},
+			"locations": schema.ListNestedAttribute{
+				Description:         "The matching locations",
+				MarkdownDescription: "The matching locations",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description:         "Unique ID of the location",
+							MarkdownDescription: "Unique ID of the location",
+							Computed:            true,
+						},
+						"uniqueness_key": schema.StringAttribute{
+							Description:         "Uniqueness key of the location",
+							MarkdownDescription: "Uniqueness key of the location",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							Description:         "Name of the location",
+							MarkdownDescription: "Name of the location",
+							Computed:            true,
+						},
+						"latitude": schema.Float64Attribute{
+							Description:         "Latitude of the location",
+							MarkdownDescription: "Latitude of the location",
+							Computed:            true,
+						},
+						"longitude": schema.Float64Attribute{
+							Description:         "Longitude of the location",
+							MarkdownDescription: "Longitude of the location",
+							Computed:            true,
+						},
+						"distance_km": schema.Float64Attribute{
+							Description:         "Distance in kilometers to the near coordinate, only set when near is used",
+							MarkdownDescription: "Distance in kilometers to the `near` coordinate, only set when `near` is used",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks the coordinate and radius of near are in range
+func (d *locationsDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var nearObject types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("near"), &nearObject)...)
+	if resp.Diagnostics.HasError() || nearObject.IsNull() || nearObject.IsUnknown() {
+		return
+	}
+
+	var near locationsNearModel
+	resp.Diagnostics.Append(nearObject.As(ctx, &near, basetypes.ObjectAsOptions{})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !near.Latitude.IsNull() && !near.Latitude.IsUnknown() && !isInRange(near.Latitude.ValueFloat64(), 90) {
+		resp.Diagnostics.AddAttributeError(path.Root("near").AtName("latitude"), "Invalid latitude",
+			fmt.Sprintf("Latitude %v is not between -90 and 90", near.Latitude.ValueFloat64()))
+	}
+
+	if !near.Longitude.IsNull() && !near.Longitude.IsUnknown() && !isInRange(near.Longitude.ValueFloat64(), 180) {
+		resp.Diagnostics.AddAttributeError(path.Root("near").AtName("longitude"), "Invalid longitude",
+			fmt.Sprintf("Longitude %v is not between -180 and 180", near.Longitude.ValueFloat64()))
+	}
+
+	if radius := near.RadiusKm.ValueFloat64(); !near.RadiusKm.IsNull() && !near.RadiusKm.IsUnknown() && (math.IsNaN(radius) || radius < 0) {
+		resp.Diagnostics.AddAttributeError(path.Root("near").AtName("radius_km"), "Invalid radius",
+			fmt.Sprintf("Radius %v km is negative", radius))
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *locationsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	//Get input
+	var state locationsDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nameRegex, err := regexp.Compile(state.NameRegex.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid name_regex", err.Error())
+		return
+	}
+
+	//Get locations
+	locations, err := d.client.ZeroTrust.GetLocations(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to retrieve locations", err.Error())
+		return
+	}
+
+	//Filter the locations
+	state.Locations = []locationsLocationModel{}
+	for _, l := range locations {
+		if !nameRegex.MatchString(l.Name) {
+			continue
+		}
+
+		location := locationsLocationModel{
+			ID:             types.StringValue(l.ID),
+			Uniqueness_key: types.StringValue(l.UniquenessKey),
+			Name:           types.StringValue(l.Name),
+			Latitude:       types.Float64Value(l.Coords.Latitude),
+			Longitude:      types.Float64Value(l.Coords.Longitude),
+			DistanceKm:     types.Float64Null(),
+		}
+
+		if state.Near != nil {
+			distance := haversineDistanceKm(state.Near.Latitude.ValueFloat64(), state.Near.Longitude.ValueFloat64(), l.Coords.Latitude, l.Coords.Longitude)
+			if distance > state.Near.RadiusKm.ValueFloat64() {
+				continue
+			}
+			location.DistanceKm = types.Float64Value(distance)
+		}
+
+		state.Locations = append(state.Locations, location)
+	}
+
+	//Nearest location first
+	if state.Near != nil {
+		sort.SliceStable(state.Locations, func(i, j int) bool {
+			return state.Locations[i].DistanceKm.ValueFloat64() < state.Locations[j].DistanceKm.ValueFloat64()
+		})
+	}
+
+	//set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// haversineDistanceKm returns the great-circle distance in kilometers between two coordinates
+func haversineDistanceKm(lat1, long1, lat2, long2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	dLat := toRadians(lat2 - lat1)
+	dLong := toRadians(long2 - long1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLong/2)*math.Sin(dLong/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,258 @@
+package auxo
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/on2itsecurity/go-auxo/v2"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &transactionflowsDataSource{}
+	_ datasource.DataSourceWithConfigure = &transactionflowsDataSource{}
+)
+
+type transactionflowsDataSource struct {
+	client *auxo.Client
+}
+
+type transactionflowsDataSourceModel struct {
+	Protectsurface   types.String                           `tfsdk:"protectsurface"`
+	Format           types.String                           `tfsdk:"format"`
+	Transactionflows []transactionflowsTransactionflowModel `tfsdk:"transactionflows"`
+	Matrix           []transactionflowsMatrixModel          `tfsdk:"matrix"`
+	Rendered         types.String                           `tfsdk:"rendered"`
+}
+
+type transactionflowsTransactionflowModel struct {
+	Protectsurface                 types.String `tfsdk:"protectsurface"`
+	Name                           types.String `tfsdk:"name"`
+	Incoming_protectsurfaces_allow types.Set    `tfsdk:"incoming_protectsurfaces_allow"`
+	Incoming_protectsurfaces_block types.Set    `tfsdk:"incoming_protectsurfaces_block"`
+	Incoming_protectsurfaces_unset types.Set    `tfsdk:"incoming_protectsurfaces_unset"`
+	Outgoing_protectsurfaces_allow types.Set    `tfsdk:"outgoing_protectsurfaces_allow"`
+	Outgoing_protectsurfaces_block types.Set    `tfsdk:"outgoing_protectsurfaces_block"`
+	Outgoing_protectsurfaces_unset types.Set    `tfsdk:"outgoing_protectsurfaces_unset"`
+}
+
+type transactionflowsMatrixModel struct {
+	From     types.String `tfsdk:"from"`
+	To       types.String `tfsdk:"to"`
+	Outgoing types.String `tfsdk:"outgoing"`
+	Incoming types.String `tfsdk:"incoming"`
+	Status   types.String `tfsdk:"status"`
+}
+
+// NewTransactionflowsDataSource is a helper function to simplify the provider implementation.
+func NewTransactionflowsDataSource() datasource.DataSource {
+	return &transactionflowsDataSource{}
+}
+
+// Metadata returns the data source type name.
+func (d *transactionflowsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_transactionflows"
+}
+
+func (d *transactionflowsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	// Retrieve the client from the provider config
+	d.client = req.ProviderData.(*auxo.Client)
+}
+
+// Schema defines the schema for the data source.
+func (d *transactionflowsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	flowSet := func(description string) schema.SetAttribute {
+		return schema.SetAttribute{
+			Description:         description,
+			MarkdownDescription: description,
+			Computed:            true,
+			ElementType:         types.StringType,
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Description:         "The incoming and outgoing flows of one or all protect surfaces, including protect surfaces managed by others, with a flow matrix which can be rendered as Graphviz DOT or Mermaid.",
+		MarkdownDescription: "The incoming and outgoing flows of one or all protect surfaces, including protect surfaces managed by others, with a flow matrix which can be rendered as Graphviz DOT or Mermaid.",
+		Attributes: map[string]schema.Attribute{
+			"protectsurface": schema.StringAttribute{
+				Description:         "Only return the flows of the protectsurface with this ID, all protectsurfaces when not set",
+				MarkdownDescription: "Only return the flows of the protectsurface with this ID, all protectsurfaces when not set",
+				Optional:            true,
+			},
+			"format": schema.StringAttribute{
+				Description:         "Render the flow matrix in this format, dot (Graphviz) or mermaid",
+				MarkdownDescription: "Render the flow matrix in this format, `dot` (Graphviz) or `mermaid`",
+				Optional:            true,
+			},
+			"transactionflows": schema.ListNestedAttribute{
+				Description:         "The flows per protectsurface",
+				MarkdownDescription: "The flows per protectsurface",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"protectsurface": schema.StringAttribute{
+							Description:         "The ID of the protectsurface",
+							MarkdownDescription: "The ID of the protectsurface",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							Description:         "The name of the protectsurface",
+							MarkdownDescription: "The name of the protectsurface",
+							Computed:            true,
+						},
+						"incoming_protectsurfaces_allow": flowSet("The IDs of the protectsurface that are allowed to send traffic to this protectsurface"),
+						"incoming_protectsurfaces_block": flowSet("The IDs of the protectsurface that are blocked to send traffic to this protectsurface"),
+						"incoming_protectsurfaces_unset": flowSet("The IDs of the protectsurface with an incoming flow to this protectsurface, which is neither allowed nor blocked (yet)"),
+						"outgoing_protectsurfaces_allow": flowSet("The IDs of the protectsurface that are allowed to send traffic to from this protectsurface"),
+						"outgoing_protectsurfaces_block": flowSet("The IDs of the protectsurface that are blocked to send traffic to from this protectsurface"),
+						"outgoing_protectsurfaces_unset": flowSet("The IDs of the protectsurface with an outgoing flow from this protectsurface, which is neither allowed nor blocked (yet)"),
+					},
+				},
+			},
+			"matrix": schema.ListNestedAttribute{
+				Description:         "Every flow between two protectsurfaces, with the definition on both sides, ordered by from and to",
+				MarkdownDescription: "Every flow between two protectsurfaces, with the definition on both sides, ordered by `from` and `to`",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"from": schema.StringAttribute{
+							Description:         "The ID of the protectsurface the flow comes from",
+							MarkdownDescription: "The ID of the protectsurface the flow comes from",
+							Computed:            true,
+						},
+						"to": schema.StringAttribute{
+							Description:         "The ID of the protectsurface the flow goes to",
+							MarkdownDescription: "The ID of the protectsurface the flow goes to",
+							Computed:            true,
+						},
+						"outgoing": schema.StringAttribute{
+							Description:         "The outgoing flow on the from protectsurface, allow, block, unset or none (not defined)",
+							MarkdownDescription: "The outgoing flow on the `from` protectsurface, `allow`, `block`, `unset` or `none` (not defined)",
+							Computed:            true,
+						},
+						"incoming": schema.StringAttribute{
+							Description:         "The incoming flow on the to protectsurface, allow, block, unset or none (not defined)",
+							MarkdownDescription: "The incoming flow on the `to` protectsurface, `allow`, `block`, `unset` or `none` (not defined)",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							Description:         "allowed when both sides allow the flow, blocked when a side blocks the flow, incomplete otherwise",
+							MarkdownDescription: "`allowed` when both sides allow the flow, `blocked` when a side blocks the flow, `incomplete` otherwise",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"rendered": schema.StringAttribute{
+				Description:         "The flow matrix rendered in the format, only set when format is set",
+				MarkdownDescription: "The flow matrix rendered in the `format`, only set when `format` is set",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *transactionflowsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	//Get input
+	var state transactionflowsDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !state.Format.IsNull() && !sliceContains(flowFormats, state.Format.ValueString()) {
+		resp.Diagnostics.AddError("Invalid format",
+			"format ["+state.Format.ValueString()+"] is not valid, use one of ["+strings.Join(flowFormats, ",")+"]")
+		return
+	}
+
+	//Get protectsurfaces
+	protectsurfaces, err := d.client.ZeroTrust.GetProtectSurfaces(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to retrieve protectsurfaces", err.Error())
+		return
+	}
+
+	sort.Slice(protectsurfaces, func(i, j int) bool { return protectsurfaces[i].ID < protectsurfaces[j].ID })
+
+	psID := state.Protectsurface.ValueString()
+	names := make(map[string]string, len(protectsurfaces))
+	found := false
+
+	//Map the flows per protectsurface
+	state.Transactionflows = []transactionflowsTransactionflowModel{}
+	for _, ps := range protectsurfaces {
+		names[ps.ID] = ps.Name
+
+		if psID != "" && ps.ID != psID {
+			continue
+		}
+		found = true
+
+		f := readFlowsFromPS(ps)
+		tf := transactionflowsTransactionflowModel{
+			Protectsurface: types.StringValue(ps.ID),
+			Name:           types.StringValue(ps.Name),
+		}
+		tf.Incoming_protectsurfaces_allow, _ = types.SetValueFrom(ctx, types.StringType, f.incomingPSAllow)
+		tf.Incoming_protectsurfaces_block, _ = types.SetValueFrom(ctx, types.StringType, f.incomingPSBlock)
+		tf.Incoming_protectsurfaces_unset, _ = types.SetValueFrom(ctx, types.StringType, f.incomingPSUnset)
+		tf.Outgoing_protectsurfaces_allow, _ = types.SetValueFrom(ctx, types.StringType, f.outgoingPSAllow)
+		tf.Outgoing_protectsurfaces_block, _ = types.SetValueFrom(ctx, types.StringType, f.outgoingPSBlock)
+		tf.Outgoing_protectsurfaces_unset, _ = types.SetValueFrom(ctx, types.StringType, f.outgoingPSUnset)
+
+		state.Transactionflows = append(state.Transactionflows, tf)
+	}
+
+	if psID != "" && !found {
+		resp.Diagnostics.AddError("Protectsurface not found", "Unable to find protectsurface with ID "+psID)
+		return
+	}
+
+	//The matrix is build from all protectsurfaces, to include the other side of the flows
+	var matrix []flowMatrixEntry
+	for _, e := range buildFlowMatrix(protectsurfaces) {
+		if psID != "" && e.from != psID && e.to != psID {
+			continue
+		}
+		matrix = append(matrix, e)
+	}
+
+	state.Matrix = []transactionflowsMatrixModel{}
+	for _, e := range matrix {
+		state.Matrix = append(state.Matrix, transactionflowsMatrixModel{
+			From:     types.StringValue(e.from),
+			To:       types.StringValue(e.to),
+			Outgoing: types.StringValue(e.outgoing),
+			Incoming: types.StringValue(e.incoming),
+			Status:   types.StringValue(e.status()),
+		})
+	}
+
+	state.Rendered = types.StringNull()
+	if !state.Format.IsNull() {
+		rendered, err := renderFlowMatrix(state.Format.ValueString(), matrix, names)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to render flow matrix", err.Error())
+			return
+		}
+		state.Rendered = types.StringValue(rendered)
+	}
+
+	//set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
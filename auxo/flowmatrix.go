@@ -0,0 +1,185 @@
+// Description: This file contains the flow matrix of the transaction flows between protect surfaces, and its rendering
+// as Graphviz DOT or Mermaid
+
+package auxo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/on2itsecurity/go-auxo/v2/zerotrust"
+)
+
+// Flow values of one side of a flow, none means the protect surface does not define the flow
+const (
+	flowAllow = "allow"
+	flowBlock = "block"
+	flowUnset = "unset"
+	flowNone  = "none"
+)
+
+// Status of a flow, based on both sides of the flow
+const (
+	flowStatusAllowed    = "allowed"
+	flowStatusBlocked    = "blocked"
+	flowStatusIncomplete = "incomplete"
+)
+
+// flowFormats are the valid values for the format attribute
+var flowFormats = []string{"dot", "mermaid"}
+
+// flowMatrixEntry is a flow from one protect surface to another, with the definition of both sides
+// outgoing is defined on the from protect surface, incoming on the to protect surface.
+type flowMatrixEntry struct {
+	from     string
+	to       string
+	outgoing string
+	incoming string
+}
+
+// status returns allowed when both sides allow the flow, blocked when a side blocks it and incomplete otherwise
+func (e flowMatrixEntry) status() string {
+	switch {
+	case e.outgoing == flowAllow && e.incoming == flowAllow:
+		return flowStatusAllowed
+	case e.outgoing == flowBlock || e.incoming == flowBlock:
+		return flowStatusBlocked
+	default:
+		return flowStatusIncomplete
+	}
+}
+
+// buildFlowMatrix returns all flows of the protect surfaces, sorted by from and to
+// A flow which is defined on only one side is included, with none for the other side.
+func buildFlowMatrix(protectsurfaces []*zerotrust.ProtectSurface) []flowMatrixEntry {
+	entries := map[[2]string]*flowMatrixEntry{}
+
+	entry := func(from, to string) *flowMatrixEntry {
+		key := [2]string{from, to}
+		if _, ok := entries[key]; !ok {
+			entries[key] = &flowMatrixEntry{from: from, to: to, outgoing: flowNone, incoming: flowNone}
+		}
+		return entries[key]
+	}
+
+	for _, ps := range protectsurfaces {
+		f := readFlowsFromPS(ps)
+
+		for value, ids := range map[string][]string{
+			flowAllow: getSliceFromSetOfString(f.outgoingPSAllow),
+			flowBlock: getSliceFromSetOfString(f.outgoingPSBlock),
+			flowUnset: getSliceFromSetOfString(f.outgoingPSUnset),
+		} {
+			for _, id := range ids {
+				entry(ps.ID, id).outgoing = value
+			}
+		}
+
+		for value, ids := range map[string][]string{
+			flowAllow: getSliceFromSetOfString(f.incomingPSAllow),
+			flowBlock: getSliceFromSetOfString(f.incomingPSBlock),
+			flowUnset: getSliceFromSetOfString(f.incomingPSUnset),
+		} {
+			for _, id := range ids {
+				entry(id, ps.ID).incoming = value
+			}
+		}
+	}
+
+	matrix := make([]flowMatrixEntry, 0, len(entries))
+	for _, e := range entries {
+		matrix = append(matrix, *e)
+	}
+	sort.Slice(matrix, func(i, j int) bool {
+		if matrix[i].from != matrix[j].from {
+			return matrix[i].from < matrix[j].from
+		}
+		return matrix[i].to < matrix[j].to
+	})
+
+	return matrix
+}
+
+// renderFlowMatrix renders the flow matrix as Graphviz DOT or Mermaid flowchart, names maps the protect surface IDs
+// to their names. Allowed flows are solid lines, blocked flows are red and incomplete flows are dashed.
+func renderFlowMatrix(format string, matrix []flowMatrixEntry, names map[string]string) (string, error) {
+	//Collect the protect surfaces in the matrix, in a stable order
+	var ids []string
+	seen := map[string]bool{}
+	for _, e := range matrix {
+		for _, id := range []string{e.from, e.to} {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+
+	label := func(id string) string {
+		if name, ok := names[id]; ok && name != "" {
+			return name
+		}
+		return id
+	}
+
+	var b strings.Builder
+
+	switch format {
+	case "dot":
+		dotQuote := func(s string) string {
+			return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+		}
+
+		b.WriteString("digraph transactionflows {\n")
+		b.WriteString("  rankdir=LR;\n")
+		for _, id := range ids {
+			fmt.Fprintf(&b, "  %s [label=%s];\n", dotQuote(id), dotQuote(label(id)))
+		}
+		for _, e := range matrix {
+			style := ""
+			switch e.status() {
+			case flowStatusBlocked:
+				style = ", color=red"
+			case flowStatusIncomplete:
+				style = ", style=dashed"
+			}
+			fmt.Fprintf(&b, "  %s -> %s [label=%s%s];\n", dotQuote(e.from), dotQuote(e.to), dotQuote(e.status()), style)
+		}
+		b.WriteString("}\n")
+	case "mermaid":
+		//Mermaid node IDs can not contain every character, the protect surfaces are numbered instead
+		nodes := make(map[string]string, len(ids))
+		for i, id := range ids {
+			nodes[id] = fmt.Sprintf("ps%d", i)
+		}
+		mermaidQuote := func(s string) string {
+			return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+		}
+
+		b.WriteString("flowchart LR\n")
+		for _, id := range ids {
+			fmt.Fprintf(&b, "  %s[%s]\n", nodes[id], mermaidQuote(label(id)))
+		}
+
+		var blocked []string
+		for i, e := range matrix {
+			arrow := "-->"
+			switch e.status() {
+			case flowStatusBlocked:
+				blocked = append(blocked, fmt.Sprint(i))
+			case flowStatusIncomplete:
+				arrow = "-.->"
+			}
+			fmt.Fprintf(&b, "  %s %s|%s| %s\n", nodes[e.from], arrow, e.status(), nodes[e.to])
+		}
+		if len(blocked) > 0 {
+			fmt.Fprintf(&b, "  linkStyle %s stroke:red\n", strings.Join(blocked, ","))
+		}
+	default:
+		return "", fmt.Errorf("format [%s] is not valid, use one of [%s]", format, strings.Join(flowFormats, ","))
+	}
+
+	return b.String(), nil
+}
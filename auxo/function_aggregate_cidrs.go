@@ -0,0 +1,61 @@
+package auxo
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &aggregateCidrsFunction{}
+
+type aggregateCidrsFunction struct{}
+
+// NewAggregateCidrsFunction is a helper function to simplify the provider implementation.
+func NewAggregateCidrsFunction() function.Function {
+	return &aggregateCidrsFunction{}
+}
+
+// Metadata returns the function name.
+func (f *aggregateCidrsFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "aggregate_cidrs"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *aggregateCidrsFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Aggregate CIDRs",
+		Description:         "Returns the smallest list of prefixes covering exactly the same addresses as the given IP addresses and prefixes, overlapping and adjacent prefixes are merged. The result is sorted, IPv4 before IPv6.",
+		MarkdownDescription: "Returns the smallest list of prefixes covering exactly the same addresses as the given IP addresses and prefixes, overlapping and adjacent prefixes are merged. The result is sorted, IPv4 before IPv6.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:                "list",
+				Description:         "IP addresses and prefixes e.g. [\"10.1.0.0/25\", \"10.1.0.128/25\"]",
+				MarkdownDescription: "IP addresses and prefixes e.g. `[\"10.1.0.0/25\", \"10.1.0.128/25\"]`",
+				ElementType:         types.StringType,
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+// Run returns the aggregated CIDRs.
+func (f *aggregateCidrsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var cidrs []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &cidrs))
+	if resp.Error != nil {
+		return
+	}
+
+	aggregated, err := aggregateCIDRs(cidrs)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, aggregated))
+}
@@ -0,0 +1,67 @@
+package auxo
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &cidrOverlapsFunction{}
+
+type cidrOverlapsFunction struct{}
+
+// NewCidrOverlapsFunction is a helper function to simplify the provider implementation.
+func NewCidrOverlapsFunction() function.Function {
+	return &cidrOverlapsFunction{}
+}
+
+// Metadata returns the function name.
+func (f *cidrOverlapsFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cidr_overlaps"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *cidrOverlapsFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Check if two CIDRs overlap",
+		Description:         "Returns true when the IP addresses or prefixes a and b have addresses in common.",
+		MarkdownDescription: "Returns `true` when the IP addresses or prefixes `a` and `b` have addresses in common.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "a",
+				Description:         "IP address or prefix e.g. 10.1.0.0/16",
+				MarkdownDescription: "IP address or prefix e.g. `10.1.0.0/16`",
+			},
+			function.StringParameter{
+				Name:                "b",
+				Description:         "IP address or prefix e.g. 10.1.1.2",
+				MarkdownDescription: "IP address or prefix e.g. `10.1.1.2`",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+// Run returns whether the CIDRs overlap.
+func (f *cidrOverlapsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var a, b string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &a, &b))
+	if resp.Error != nil {
+		return
+	}
+
+	if _, _, err := parseCIDR(a); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	overlaps, err := cidrsOverlap(a, b)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, overlaps))
+}
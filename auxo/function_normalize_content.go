@@ -0,0 +1,62 @@
+package auxo
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &normalizeContentFunction{}
+
+type normalizeContentFunction struct{}
+
+// NewNormalizeContentFunction is a helper function to simplify the provider implementation.
+func NewNormalizeContentFunction() function.Function {
+	return &normalizeContentFunction{}
+}
+
+// Metadata returns the function name.
+func (f *normalizeContentFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "normalize_content"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *normalizeContentFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Normalize the content of a state",
+		Description:         "Returns the content as normalized by the auxo_state resource; ipv4 and ipv6 addresses and prefixes in their canonical form (with the host bits of prefixes cleared), other content trimmed.",
+		MarkdownDescription: "Returns the content as normalized by the `auxo_state` resource; `ipv4` and `ipv6` addresses and prefixes in their canonical form (with the host bits of prefixes cleared), other content trimmed.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "type",
+				Description:         "Content type of the state i.e. ipv4, ipv6, azure_resource",
+				MarkdownDescription: "Content type of the state i.e. `ipv4`, `ipv6`, `azure_resource`",
+			},
+			function.StringParameter{
+				Name:                "value",
+				Description:         "Content to normalize e.g. 10.1.1.2/24",
+				MarkdownDescription: "Content to normalize e.g. `10.1.1.2/24`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run returns the normalized content.
+func (f *normalizeContentFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var contentType, value string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &contentType, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	normalized, err := normalizeContent(contentType, value)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, normalized))
+}
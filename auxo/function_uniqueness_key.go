@@ -0,0 +1,55 @@
+package auxo
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &uniquenessKeyFunction{}
+
+type uniquenessKeyFunction struct{}
+
+// NewUniquenessKeyFunction is a helper function to simplify the provider implementation.
+func NewUniquenessKeyFunction() function.Function {
+	return &uniquenessKeyFunction{}
+}
+
+// Metadata returns the function name.
+func (f *uniquenessKeyFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "uniqueness_key"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *uniquenessKeyFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Build a uniqueness key",
+		Description:         "Returns a uniqueness key made of the parts, every part is lower cased and characters other than a-z and 0-9 are replaced by a dash, the parts are joined by a dot e.g. uniqueness_key(\"Web Servers\", \"PROD\") returns web-servers.prod.",
+		MarkdownDescription: "Returns a uniqueness key made of the parts, every part is lower cased and characters other than `a-z` and `0-9` are replaced by a dash, the parts are joined by a dot e.g. `uniqueness_key(\"Web Servers\", \"PROD\")` returns `web-servers.prod`.",
+		VariadicParameter: function.StringParameter{
+			Name:                "parts",
+			Description:         "Parts of the uniqueness key, at least one",
+			MarkdownDescription: "Parts of the uniqueness key, at least one",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run returns the uniqueness key.
+func (f *uniquenessKeyFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var parts []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &parts))
+	if resp.Error != nil {
+		return
+	}
+
+	key, err := uniquenessKey(parts)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, key))
+}
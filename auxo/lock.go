@@ -0,0 +1,71 @@
+// Description: This file contains a keyed lock, which serializes the changes of a protect surface
+
+package auxo
+
+import (
+	"sort"
+	"sync"
+)
+
+// keyedMutex serializes the work per key (e.g. a protect surface ID), work on different keys runs in parallel
+type keyedMutex struct {
+	m     sync.Mutex
+	locks map[string]*keyedLock
+}
+
+// keyedLock is the lock of a single key, refs counts the holders and waiters, so unused locks can be removed
+type keyedLock struct {
+	m    sync.Mutex
+	refs int
+}
+
+// lock locks the key and returns the function to unlock it
+func (k *keyedMutex) lock(key string) func() {
+	k.m.Lock()
+	if k.locks == nil {
+		k.locks = map[string]*keyedLock{}
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.m.Unlock()
+
+	l.m.Lock()
+
+	return func() {
+		l.m.Unlock()
+
+		k.m.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.m.Unlock()
+	}
+}
+
+// lockAll locks the keys in sorted order, so holders of overlapping keys can not deadlock, and returns the function
+// to unlock them. Duplicate keys are locked once.
+func (k *keyedMutex) lockAll(keys ...string) func() {
+	sorted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !sliceContains(sorted, key) {
+			sorted = append(sorted, key)
+		}
+	}
+	sort.Strings(sorted)
+
+	unlocks := make([]func(), 0, len(sorted))
+	for _, key := range sorted {
+		unlocks = append(unlocks, k.lock(key))
+	}
+
+	return func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+}
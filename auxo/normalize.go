@@ -0,0 +1,170 @@
+// Description: This file contains the normalization of state content, CIDRs and uniqueness keys, shared by the
+// auxo_state resource and the provider functions
+
+package auxo
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// uniquenessKeyInvalidChars matches the characters which are replaced in a uniqueness key part
+var uniquenessKeyInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// normalizeContent returns the normalized content of a state for the content type
+// ipv4 and ipv6 addresses and prefixes are returned in their canonical form, with the host bits of prefixes cleared,
+// addresses without prefix length are kept as address. Other content is only trimmed.
+func normalizeContent(contentType, value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	switch contentType {
+	case "ipv4", "ipv6":
+		prefix, isPrefix, err := parseCIDR(value)
+		if err != nil {
+			return "", err
+		}
+
+		if (contentType == "ipv4") != prefix.Addr().Is4() {
+			return "", fmt.Errorf("%s is not an %s address or prefix", value, contentType)
+		}
+
+		if !isPrefix {
+			return prefix.Addr().String(), nil
+		}
+
+		return prefix.String(), nil
+	default:
+		if value == "" {
+			return "", fmt.Errorf("content can not be empty")
+		}
+
+		return value, nil
+	}
+}
+
+// parseCIDR parses an IP address or prefix, an address is returned as single address prefix (/32 or /128)
+// The host bits of the prefix are cleared, isPrefix is false when value is an address.
+func parseCIDR(value string) (prefix netip.Prefix, isPrefix bool, err error) {
+	value = strings.TrimSpace(value)
+
+	if strings.Contains(value, "/") {
+		prefix, err = netip.ParsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, false, fmt.Errorf("%s is not a valid IP prefix", value)
+		}
+
+		return prefix.Masked(), true, nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil || addr.Zone() != "" {
+		return netip.Prefix{}, false, fmt.Errorf("%s is not a valid IP address", value)
+	}
+	addr = addr.Unmap()
+
+	return netip.PrefixFrom(addr, addr.BitLen()), false, nil
+}
+
+// cidrsOverlap returns true when the addresses or prefixes a and b have addresses in common
+func cidrsOverlap(a, b string) (bool, error) {
+	prefixA, _, err := parseCIDR(a)
+	if err != nil {
+		return false, err
+	}
+
+	prefixB, _, err := parseCIDR(b)
+	if err != nil {
+		return false, err
+	}
+
+	return prefixA.Overlaps(prefixB), nil
+}
+
+// aggregateCIDRs returns the smallest list of prefixes covering exactly the same addresses as the given addresses and
+// prefixes, sorted with IPv4 before IPv6
+func aggregateCIDRs(values []string) ([]string, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, v := range values {
+		prefix, _, err := parseCIDR(v)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	for {
+		sort.Slice(prefixes, func(i, j int) bool {
+			if c := prefixes[i].Addr().Compare(prefixes[j].Addr()); c != 0 {
+				return c < 0
+			}
+			return prefixes[i].Bits() < prefixes[j].Bits()
+		})
+
+		merged := make([]netip.Prefix, 0, len(prefixes))
+		changed := false
+
+		for _, p := range prefixes {
+			if len(merged) == 0 {
+				merged = append(merged, p)
+				continue
+			}
+
+			last := merged[len(merged)-1]
+
+			//Skip prefixes which are covered by the previous prefix (prefixes are sorted by address)
+			if last.Bits() <= p.Bits() && last.Contains(p.Addr()) {
+				changed = changed || last != p
+				continue
+			}
+
+			//Merge two adjacent halves of the same parent prefix
+			if last.Bits() == p.Bits() && p.Bits() > 0 {
+				parent := netip.PrefixFrom(last.Addr(), last.Bits()-1).Masked()
+				if parent.Addr() == last.Addr() && parent.Contains(p.Addr()) {
+					merged[len(merged)-1] = parent
+					changed = true
+					continue
+				}
+			}
+
+			merged = append(merged, p)
+		}
+
+		prefixes = merged
+		if !changed {
+			break
+		}
+	}
+
+	result := make([]string, 0, len(prefixes))
+	for _, p := range prefixes {
+		result = append(result, p.String())
+	}
+
+	return result, nil
+}
+
+// uniquenessKey returns a uniqueness key made of the parts, every part is lower cased and characters other than
+// a-z and 0-9 are replaced by a dash, the parts are joined by a dot
+func uniquenessKey(parts []string) (string, error) {
+	if len(parts) == 0 {
+		return "", fmt.Errorf("at least one part is required")
+	}
+
+	normalized := make([]string, 0, len(parts))
+	for i, part := range parts {
+		part = uniquenessKeyInvalidChars.ReplaceAllString(strings.ToLower(strings.TrimSpace(part)), "-")
+		part = strings.Trim(part, "-")
+
+		if part == "" {
+			return "", fmt.Errorf("part %d does not contain any letters or digits", i+1)
+		}
+
+		normalized = append(normalized, part)
+	}
+
+	return strings.Join(normalized, "."), nil
+}
@@ -0,0 +1,91 @@
+package auxo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestAggregateCIDRs(t *testing.T) {
+	tests := map[string]struct {
+		values  []string
+		want    []string
+		wantErr bool
+	}{
+		"empty": {
+			values: []string{},
+			want:   []string{},
+		},
+		"overlapping": {
+			values: []string{"10.0.0.0/23", "10.0.1.0/24", "10.0.1.128/25"},
+			want:   []string{"10.0.0.0/23"},
+		},
+		"duplicate": {
+			values: []string{"10.0.0.0/24", "10.0.0.0/25", "10.0.0.0/24"},
+			want:   []string{"10.0.0.0/24"},
+		},
+		"adjacent": {
+			values: []string{"10.0.1.0/24", "10.0.0.0/24"},
+			want:   []string{"10.0.0.0/23"},
+		},
+		"adjacent addresses": {
+			values: []string{"192.168.1.1", "192.168.1.0", "192.168.1.2", "192.168.1.3"},
+			want:   []string{"192.168.1.0/30"},
+		},
+		"adjacent across parents": {
+			values: []string{"10.0.1.0/24", "10.0.2.0/24"},
+			want:   []string{"10.0.1.0/24", "10.0.2.0/24"},
+		},
+		"adjacent merged repeatedly": {
+			values: []string{"10.0.0.0/25", "10.0.0.128/25", "10.0.1.0/24", "10.0.2.0/23"},
+			want:   []string{"10.0.0.0/22"},
+		},
+		"contained": {
+			values: []string{"10.1.2.3", "10.0.0.0/8", "10.200.0.0/16"},
+			want:   []string{"10.0.0.0/8"},
+		},
+		"host bits": {
+			values: []string{"10.0.0.1/24"},
+			want:   []string{"10.0.0.0/24"},
+		},
+		"mixed family": {
+			values: []string{"2001:db8::/33", "10.0.1.0/24", "2001:db8:8000::/33", "10.0.0.0/24", "::ffff:10.0.2.1"},
+			want:   []string{"10.0.0.0/23", "10.0.2.1/32", "2001:db8::/32"},
+		},
+		"invalid": {
+			values:  []string{"10.0.0.0/24", "10.0.0.256"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := aggregateCIDRs(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestKeepConfiguredContent(t *testing.T) {
+	ctx := context.Background()
+	content, _ := types.SetValueFrom(ctx, types.StringType, []string{"10.0.0.0/24", "10.0.1.1"})
+	configured, _ := types.SetValueFrom(ctx, types.StringType, []string{"10.0.0.1/24"})
+
+	got, diags := keepConfiguredContent(ctx, types.StringValue("ipv4"), content, configured)
+	if diags.HasError() {
+		t.Fatalf("unexpected error %v", diags)
+	}
+
+	want, _ := types.SetValueFrom(ctx, types.StringType, []string{"10.0.0.1/24", "10.0.1.1"})
+	if !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
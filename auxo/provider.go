@@ -2,43 +2,76 @@ package auxo
 
 import (
 	"context"
-	"os"
-	"sync"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/on2itsecurity/go-auxo/v2"
+	"github.com/on2itsecurity/go-auxo/v2/asset"
+	"github.com/on2itsecurity/go-auxo/v2/crm"
+	"github.com/on2itsecurity/go-auxo/v2/zerotrust"
 )
 
 // Ensure the implementation satisfies the provider.Provider interface.
 var _ provider.Provider = (*auxoProvider)(nil)
+var _ provider.ProviderWithFunctions = (*auxoProvider)(nil)
 
 // auxoProvider represents the provider.Provider interface
-type auxoProvider struct{}
+type auxoProvider struct {
+	// version is the provider version, set by goreleaser or "dev"
+	version string
+	tokens  tokenCache
+}
 
 type auxoProviderModel struct {
-	Url    types.String `tfsdk:"url"`
-	Token  types.String `tfsdk:"token"`
-	Name   types.String `tfsdk:"name"`
-	Config types.String `tfsdk:"config"`
+	Url                 types.String   `tfsdk:"url"`
+	Token               types.String   `tfsdk:"token"`
+	TokenFile           types.String   `tfsdk:"token_file"`
+	TokenCommand        types.List     `tfsdk:"token_command"`
+	TokenCommandTimeout types.String   `tfsdk:"token_command_timeout"`
+	Name                types.String   `tfsdk:"name"`
+	Config              types.String   `tfsdk:"config"`
+	ConfigPaths         types.List     `tfsdk:"config_paths"`
+	DeletionProtection  types.Bool     `tfsdk:"deletion_protection"`
+	ReadOnly            types.Bool     `tfsdk:"read_only"`
+	CoordinatePrecision types.Float64  `tfsdk:"coordinate_precision"`
+	Defaults            *defaultsModel `tfsdk:"defaults"`
+}
+
+type defaultsModel struct {
+	CustomerLabels types.Map `tfsdk:"customer_labels"`
+	DataTags       types.Set `tfsdk:"data_tags"`
+	ComplianceTags types.Set `tfsdk:"compliance_tags"`
+	SOCTags        types.Set `tfsdk:"soc_tags"`
 }
 
 type auxoClient struct {
-	client *auxo.Client
-	m      *sync.Mutex
+	client              *auxo.Client
+	locks               *keyedMutex
+	deletionProtection  bool
+	coordinatePrecision float64
+	readOnly            bool
+	contacts            *listCache[crm.Contact]
+	assets              *listCache[asset.AssetItem]
+	protectsurfaces     *listCache[zerotrust.ProtectSurface]
+	defaults            protectsurfaceDefaults
 }
 
-// New returns a new provider.Provider.
-func New() provider.Provider {
-	return &auxoProvider{}
+// New returns a function which returns a new provider.Provider, for the given version.
+func New(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &auxoProvider{version: version}
+	}
 }
 
 func (p *auxoProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "auxo"
-	resp.Version = "dev"
+	resp.Version = p.version
 }
 
 func (p *auxoProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
@@ -46,71 +79,199 @@ func (p *auxoProvider) Schema(ctx context.Context, req provider.SchemaRequest, r
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "The alias in the ztctl configuration file, this takes precedence over the url and token attributes",
-				Description:         "The alias in the ztctl configuration file, this takes precedence over the url and token attributes",
+				MarkdownDescription: "The alias in the ztctl configuration file, this takes precedence over the url and token attributes, can also be set with the `AUXO_PROFILE` environment variable, which is ignored when `url` or the token is set in the configuration",
+				Description:         "The alias in the ztctl configuration file, this takes precedence over the url and token attributes, can also be set with the AUXO_PROFILE environment variable, which is ignored when url or the token is set in the configuration",
 			},
 			"config": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "Location of the ztctl configuration file, will default to `~/.ztctl/config.json`",
-				Description:         "Location of the ztctl configuration file, will default to `~/.ztctl/config.json`",
+				MarkdownDescription: "Location of the ztctl configuration file (JSON or YAML), will default to searching `$XDG_CONFIG_HOME/ztctl/` and `~/.ztctl/` for `config.json`, `config.yaml` or `config.yml`, can also be set with the `AUXO_CONFIG_FILE` environment variable",
+				Description:         "Location of the ztctl configuration file (JSON or YAML), will default to searching $XDG_CONFIG_HOME/ztctl/ and ~/.ztctl/ for config.json, config.yaml or config.yml, can also be set with the AUXO_CONFIG_FILE environment variable",
+			},
+			"config_paths": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Locations of ztctl configuration files (JSON or YAML), searched in order for the alias in `name`, `config` takes precedence, can also be set with the `AUXO_CONFIG_PATHS` environment variable (separated by the OS path list separator)",
+				Description:         "Locations of ztctl configuration files (JSON or YAML), searched in order for the alias in name, config takes precedence, can also be set with the AUXO_CONFIG_PATHS environment variable (separated by the OS path list separator)",
 			},
 			"url": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "The URL of the Auxo API",
-				Description:         "The URL of the Auxo API",
+				MarkdownDescription: "The URL of the Auxo API, can also be set with the `AUXO_URL` environment variable, defaults to `api.on2it.net`",
+				Description:         "The URL of the Auxo API, can also be set with the AUXO_URL environment variable, defaults to api.on2it.net",
 			},
 			"token": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "The token to access the API",
-				Description:         "The token to access the API",
+				MarkdownDescription: "The token to access the API, can also be set with the `AUXO_TOKEN` environment variable",
+				Description:         "The token to access the API, can also be set with the AUXO_TOKEN environment variable",
 				Sensitive:           true,
 			},
+			"token_file": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "File containing the token to access the API, can also be set with the `AUXO_TOKEN_FILE` environment variable",
+				Description:         "File containing the token to access the API, can also be set with the AUXO_TOKEN_FILE environment variable",
+			},
+			"token_command": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Command (executable followed by its arguments) which prints the token to access the API on stdout, e.g. a credential helper, can also be set with the `AUXO_TOKEN_COMMAND` environment variable (separated by spaces)",
+				Description:         "Command (executable followed by its arguments) which prints the token to access the API on stdout, e.g. a credential helper, can also be set with the AUXO_TOKEN_COMMAND environment variable (separated by spaces)",
+			},
+			"token_command_timeout": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Maximum duration of the `token_command`, e.g. `10s`, can also be set with the `AUXO_TOKEN_COMMAND_TIMEOUT` environment variable, defaults to `30s`",
+				Description:         "Maximum duration of the token_command, e.g. 10s, can also be set with the AUXO_TOKEN_COMMAND_TIMEOUT environment variable, defaults to 30s",
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Default for the `deletion_protection` attribute of protect surfaces and locations, can also be set with the `AUXO_DELETION_PROTECTION` environment variable, defaults to `false`",
+				Description:         "Default for the deletion_protection attribute of protect surfaces and locations, can also be set with the AUXO_DELETION_PROTECTION environment variable, defaults to false",
+			},
+			"read_only": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Block every create, update and delete of resources, reads and data sources keep working (e.g. for safe plans against production), can also be set with the `AUXO_READ_ONLY` environment variable, defaults to `false`",
+				Description:         "Block every create, update and delete of resources, reads and data sources keep working (e.g. for safe plans against production), can also be set with the AUXO_READ_ONLY environment variable, defaults to false",
+			},
+			"coordinate_precision": schema.Float64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Differences in the latitude and longitude of locations below this precision (in degrees) are ignored, can also be set with the `AUXO_COORDINATE_PRECISION` environment variable, defaults to `1e-7`",
+				Description:         "Differences in the latitude and longitude of locations below this precision (in degrees) are ignored, can also be set with the AUXO_COORDINATE_PRECISION environment variable, defaults to 1e-7",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"defaults": schema.SingleNestedBlock{
+				MarkdownDescription: "Default values which are merged into every `auxo_protectsurface`, values set on the resource take precedence",
+				Description:         "Default values which are merged into every auxo_protectsurface, values set on the resource take precedence",
+				Attributes: map[string]schema.Attribute{
+					"customer_labels": schema.MapAttribute{
+						Optional:            true,
+						ElementType:         types.StringType,
+						MarkdownDescription: "Customer labels added to every protect surface",
+						Description:         "Customer labels added to every protect surface",
+					},
+					"data_tags": schema.SetAttribute{
+						Optional:            true,
+						ElementType:         types.StringType,
+						MarkdownDescription: "Data tags added to every protect surface",
+						Description:         "Data tags added to every protect surface",
+					},
+					"compliance_tags": schema.SetAttribute{
+						Optional:            true,
+						ElementType:         types.StringType,
+						MarkdownDescription: "Compliance tags added to every protect surface",
+						Description:         "Compliance tags added to every protect surface",
+					},
+					"soc_tags": schema.SetAttribute{
+						Optional:            true,
+						ElementType:         types.StringType,
+						MarkdownDescription: "SOC tags added to every protect surface, only use when advised by the SOC",
+						Description:         "SOC tags added to every protect surface, only use when advised by the SOC",
+					},
+				},
+			},
 		},
 	}
 }
 
 func (p *auxoProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
-	//Default or environment variables
-	// checkov:skip=CKV_SECRET_6: False/Positive
-	token := os.Getenv("AUXO_TOKEN")
-	url := "api.on2it.net"
-	config := getDefaultConfigLocation()
-
 	var data auxoProviderModel
 
 	// Read configuration data into model
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 
-	// Check configuration data, which should take precedence over
-	// environment variable data, if found.
-	if data.Token.ValueString() != "" {
-		token = data.Token.ValueString()
-	}
+	// Configuration data takes precedence over environment variables, which take precedence over the defaults.
+	// checkov:skip=CKV_SECRET_6: False/Positive
+	token, tokenSource := "", sourceDefault
+	url, urlSource := stringSetting(data.Url, "AUXO_URL", "api.on2it.net")
+	alias, aliasSource := stringSetting(data.Name, "AUXO_PROFILE", "")
+	config, configSource := stringSetting(data.Config, "AUXO_CONFIG_FILE", "")
 
-	if data.Url.ValueString() != "" {
-		url = data.Url.ValueString()
-	}
+	configPaths, configPathsSource, diags := stringListSetting(ctx, data.ConfigPaths, "AUXO_CONFIG_PATHS")
+	resp.Diagnostics.Append(diags...)
 
-	if data.Config.ValueString() != "" {
-		config = data.Config.ValueString()
+	//A profile from the environment does not override the url or token of the provider configuration
+	credentialsConfigured := data.Url.ValueString() != "" || data.Token.ValueString() != "" ||
+		data.TokenFile.ValueString() != "" || len(data.TokenCommand.Elements()) > 0
+	if alias != "" && aliasSource != sourceConfiguration && credentialsConfigured {
+		resp.Diagnostics.AddWarning("Profile from environment ignored",
+			"The ztctl profile "+alias+" from the "+aliasSource+" is ignored, because the url or token is set in the provider configuration. "+
+				"Set the profile with the name attribute to use it instead.")
+		alias, aliasSource = "", sourceDefault
 	}
 
-	if data.Name.ValueString() != "" {
-		alias := data.Name.ValueString()
+	if alias != "" {
+		//The config file takes precedence over the config paths, which take precedence over the default locations
+		var configFiles []string
+		var err error
+
+		switch {
+		case config != "":
+			configFiles = []string{config}
+		case len(configPaths) > 0:
+			configFiles = configPaths
+		default:
+			configFiles, err = getDefaultConfigLocations()
+		}
 
 		//Read configuration and set url and token
-		cfg, err := getConfig(config, alias)
+		var cfg ConfigEntry
+		if err == nil {
+			cfg, err = getConfig(configFiles, alias)
+		}
 
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Unable to read configuration file",
 				"An unexpected error occurred when reading the configuration file. "+
 					"client error: "+err.Error())
+		} else {
+			//Found specific alias, set url and token
+			url, urlSource = cfg.APIAddress, sourceProfile
+			token, tokenSource = cfg.Token, sourceProfile
 		}
+	} else {
+		token, tokenSource, diags = p.resolveToken(ctx, &data)
+		resp.Diagnostics.Append(diags...)
+	}
 
-		//Find specific alias and set url and token
-		url = cfg.APIAddress
-		token = cfg.Token
+	deletionProtection, deletionProtectionSource, err := boolSetting(data.DeletionProtection, "AUXO_DELETION_PROTECTION", false)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("deletion_protection"), "Invalid environment variable", err.Error())
+	}
+
+	readOnly, readOnlySource, err := boolSetting(data.ReadOnly, "AUXO_READ_ONLY", false)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("read_only"), "Invalid environment variable", err.Error())
+	}
+
+	precision, precisionSource, err := float64Setting(data.CoordinatePrecision, "AUXO_COORDINATE_PRECISION", defaultCoordinatePrecision)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("coordinate_precision"), "Invalid environment variable", err.Error())
+	}
+
+	//Log where the settings came from, never log the token itself
+	tflog.Debug(ctx, "Resolved AUXO provider settings", map[string]interface{}{
+		"url":                         url,
+		"url_source":                  urlSource,
+		"token_source":                tokenSource,
+		"profile":                     alias,
+		"profile_source":              aliasSource,
+		"config_file":                 config,
+		"config_file_source":          configSource,
+		"config_paths":                configPaths,
+		"config_paths_source":         configPathsSource,
+		"deletion_protection":         deletionProtection,
+		"deletion_protection_source":  deletionProtectionSource,
+		"read_only":                   readOnly,
+		"read_only_source":            readOnlySource,
+		"coordinate_precision":        precision,
+		"coordinate_precision_source": precisionSource,
+	})
+
+	var defaults protectsurfaceDefaults
+	if data.Defaults != nil {
+		resp.Diagnostics.Append(data.Defaults.CustomerLabels.ElementsAs(ctx, &defaults.customerLabels, false)...)
+		resp.Diagnostics.Append(data.Defaults.DataTags.ElementsAs(ctx, &defaults.dataTags, false)...)
+		resp.Diagnostics.Append(data.Defaults.ComplianceTags.ElementsAs(ctx, &defaults.complianceTags, false)...)
+		resp.Diagnostics.Append(data.Defaults.SOCTags.ElementsAs(ctx, &defaults.socTags, false)...)
 	}
 
 	//Error checking
@@ -118,8 +279,8 @@ func (p *auxoProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		resp.Diagnostics.AddError(
 			"Missing API Token Configuration",
 			"While configuring the provider, the API token was not found in "+
-				"the AUXO_TOKEN environment variable or provider "+
-				"configuration block 'token' or 'config' attribute.",
+				"the AUXO_TOKEN, AUXO_TOKEN_FILE or AUXO_TOKEN_COMMAND environment variables, the provider "+
+				"configuration block 'token', 'token_file' or 'token_command' attribute or the ztctl profile.",
 		)
 	}
 
@@ -127,7 +288,8 @@ func (p *auxoProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		resp.Diagnostics.AddError(
 			"Missing API URL Configuration",
 			"While configuring the provider, the API URL was not found in "+
-				"the provider configuration block 'url' or 'config' attribute.",
+				"the AUXO_URL environment variable, the provider "+
+				"configuration block 'url' attribute or the ztctl profile.",
 		)
 	}
 
@@ -135,8 +297,15 @@ func (p *auxoProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 	// resp.ResourceData as appropriate.
 	client, err := auxo.NewClient(url, token, false)
 	c := &auxoClient{
-		client: client,
-		m:      &sync.Mutex{},
+		client:              client,
+		locks:               &keyedMutex{},
+		deletionProtection:  deletionProtection,
+		coordinatePrecision: precision,
+		readOnly:            readOnly,
+		contacts:            &listCache[crm.Contact]{},
+		assets:              &listCache[asset.AssetItem]{},
+		protectsurfaces:     &listCache[zerotrust.ProtectSurface]{},
+		defaults:            defaults,
 	}
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to create AUXO API client",
@@ -161,8 +330,22 @@ func (p *auxoProvider) Resources(ctx context.Context) []func() resource.Resource
 func (p *auxoProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewAssetDataSource,
+		NewAssetsDataSource,
 		NewContactDataSource,
+		NewContactsDataSource,
+		NewFlowAuditDataSource,
 		NewLocationDataSource,
+		NewLocationsDataSource,
 		NewProtectsurfaceDataSource,
+		NewTransactionflowsDataSource,
+	}
+}
+
+func (p *auxoProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewAggregateCidrsFunction,
+		NewCidrOverlapsFunction,
+		NewNormalizeContentFunction,
+		NewUniquenessKeyFunction,
 	}
 }
@@ -2,10 +2,15 @@ package auxo
 
 import (
 	"context"
-	"sync"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -16,18 +21,25 @@ import (
 
 // Ensure the implementation satisfies the resource.Resource interface.
 var _ resource.Resource = &locationResource{}
+var _ resource.ResourceWithValidateConfig = &locationResource{}
+var _ resource.ResourceWithModifyPlan = &locationResource{}
 
 type locationResource struct {
-	client *auxo.Client
-	mutex  *sync.Mutex
+	client              *auxo.Client
+	deletionProtection  bool
+	coordinatePrecision float64
+	readOnly            bool
 }
 
 type locationResourceModel struct {
-	ID             types.String  `tfsdk:"id"`
-	Uniqueness_key types.String  `tfsdk:"uniqueness_key"`
-	Name           types.String  `tfsdk:"name"`
-	Latitude       types.Float64 `tfsdk:"latitude"`
-	Longitude      types.Float64 `tfsdk:"longitude"`
+	ID                 types.String    `tfsdk:"id"`
+	Uniqueness_key     types.String    `tfsdk:"uniqueness_key"`
+	Name               types.String    `tfsdk:"name"`
+	Latitude           coordinateValue `tfsdk:"latitude"`
+	Longitude          coordinateValue `tfsdk:"longitude"`
+	Coordinates        types.String    `tfsdk:"coordinates"`
+	DeletionProtection types.Bool      `tfsdk:"deletion_protection"`
+	ForceDelete        types.Bool      `tfsdk:"force_delete"`
 }
 
 func NewLocationResource() resource.Resource {
@@ -46,7 +58,9 @@ func (r *locationResource) Configure(_ context.Context, req resource.ConfigureRe
 	// Retrieve the client from the provider config
 	c := req.ProviderData.(*auxoClient)
 	r.client = c.client
-	r.mutex = c.m
+	r.deletionProtection = c.deletionProtection
+	r.coordinatePrecision = c.coordinatePrecision
+	r.readOnly = c.readOnly
 }
 
 func (r *locationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -80,24 +94,116 @@ func (r *locationResource) Schema(ctx context.Context, req resource.SchemaReques
 				Required:            true,
 			},
 			"latitude": schema.Float64Attribute{
-				Description:         "Latitude of the resource location",
-				MarkdownDescription: "Latitude of the resource location",
+				Description:         "Latitude of the resource location, between -90 and 90",
+				MarkdownDescription: "Latitude of the resource location, between -90 and 90",
 				Optional:            true,
 				Computed:            true,
+				CustomType:          coordinateType{},
 				Default:             float64default.StaticFloat64(0),
 			},
 			"longitude": schema.Float64Attribute{
-				Description:         "Longitude of the resource location",
-				MarkdownDescription: "Longitude of the resource location",
+				Description:         "Longitude of the resource location, between -180 and 180",
+				MarkdownDescription: "Longitude of the resource location, between -180 and 180",
 				Optional:            true,
 				Computed:            true,
+				CustomType:          coordinateType{},
 				Default:             float64default.StaticFloat64(0),
 			},
+			"coordinates": schema.StringAttribute{
+				Description:         "Latitude and longitude of the resource location as one string, e.g. \"51.79,5.25\" (conflicts with latitude and longitude)",
+				MarkdownDescription: "Latitude and longitude of the resource location as one string, e.g. `\"51.79,5.25\"` (conflicts with `latitude` and `longitude`)",
+				Optional:            true,
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Description:         "Prevent the location from being deleted, defaults to the provider deletion_protection setting",
+				MarkdownDescription: "Prevent the location from being deleted, defaults to the provider `deletion_protection` setting",
+				Optional:            true,
+			},
+			"force_delete": schema.BoolAttribute{
+				Description:         "Delete the location, even when it is still referenced by one or more states",
+				MarkdownDescription: "Delete the location, even when it is still referenced by one or more states",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
 		},
 	}
 }
 
+func (r *locationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config locationResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Coordinates.IsNull() && (!config.Latitude.IsNull() || !config.Longitude.IsNull()) {
+		resp.Diagnostics.AddAttributeError(path.Root("coordinates"), "Conflicting coordinate attributes",
+			"Either coordinates OR latitude and longitude can be set")
+		return
+	}
+
+	latitude, longitude := config.Latitude.Float64Value, config.Longitude.Float64Value
+	latitudePath, longitudePath := path.Root("latitude"), path.Root("longitude")
+	if !config.Coordinates.IsNull() && !config.Coordinates.IsUnknown() {
+		var err error
+		latitude, longitude, err = parseCoordinates(config.Coordinates.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("coordinates"), "Invalid coordinates", err.Error())
+			return
+		}
+		latitudePath, longitudePath = path.Root("coordinates"), path.Root("coordinates")
+	}
+
+	if !latitude.IsNull() && !latitude.IsUnknown() && !isInRange(latitude.ValueFloat64(), 90) {
+		resp.Diagnostics.AddAttributeError(latitudePath, "Invalid latitude",
+			fmt.Sprintf("Latitude %v is not between -90 and 90", latitude.ValueFloat64()))
+	}
+
+	if !longitude.IsNull() && !longitude.IsUnknown() && !isInRange(longitude.ValueFloat64(), 180) {
+		resp.Diagnostics.AddAttributeError(longitudePath, "Invalid longitude",
+			fmt.Sprintf("Longitude %v is not between -180 and 180", longitude.ValueFloat64()))
+	}
+}
+
+func (r *locationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	//Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config locationResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || config.Coordinates.IsNull() {
+		return
+	}
+
+	//Set latitude and longitude from the coordinates
+	latitude, longitude := types.Float64Unknown(), types.Float64Unknown()
+	if !config.Coordinates.IsUnknown() {
+		var err error
+		latitude, longitude, err = parseCoordinates(config.Coordinates.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("coordinates"), "Invalid coordinates", err.Error())
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("latitude"), coordinateValue{Float64Value: latitude})...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("longitude"), coordinateValue{Float64Value: longitude})...)
+}
+
 func (r *locationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if isReadOnlyBlocked(r.readOnly, "create", "location", &resp.Diagnostics) {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan locationResourceModel
 
@@ -120,7 +226,7 @@ func (r *locationResource) Create(ctx context.Context, req resource.CreateReques
 	}
 
 	// Map resonse to schema
-	plan = locationToResourceModel(result)
+	plan = locationToResourceModel(result, &plan, r.coordinatePrecision)
 
 	// Set state
 	diags = resp.State.Set(ctx, &plan)
@@ -143,9 +249,7 @@ func (r *locationResource) Read(ctx context.Context, req resource.ReadRequest, r
 	// Get refreshed location from AUXO
 	result, err := r.client.ZeroTrust.GetLocationByID(ctx, location.ID.ValueString())
 	if err != nil {
-		apiError := getAPIError(err)
-
-		if apiError.ID == "410" { // Location not found and probably deleted
+		if isNotFoundError(err) { // Location not found and probably deleted
 			resp.State.RemoveResource(ctx)
 			return
 		} else {
@@ -155,7 +259,7 @@ func (r *locationResource) Read(ctx context.Context, req resource.ReadRequest, r
 	}
 
 	//Overwrite state with refreshed location
-	location = locationToResourceModel(result)
+	location = locationToResourceModel(result, &location, r.coordinatePrecision)
 
 	//Set refreshed state
 	diags = resp.State.Set(ctx, &location)
@@ -166,6 +270,10 @@ func (r *locationResource) Read(ctx context.Context, req resource.ReadRequest, r
 }
 
 func (r *locationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if isReadOnlyBlocked(r.readOnly, "update", "location", &resp.Diagnostics) {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan, state locationResourceModel
 
@@ -190,7 +298,7 @@ func (r *locationResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 
 	// Update state
-	plan = locationToResourceModel(result)
+	plan = locationToResourceModel(result, &plan, r.coordinatePrecision)
 	diags = resp.State.Set(ctx, plan)
 
 	resp.Diagnostics.Append(diags...)
@@ -200,6 +308,10 @@ func (r *locationResource) Update(ctx context.Context, req resource.UpdateReques
 }
 
 func (r *locationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if isReadOnlyBlocked(r.readOnly, "delete", "location", &resp.Diagnostics) {
+		return
+	}
+
 	//Retrieve values from state
 	var location locationResourceModel
 	diags := req.State.Get(ctx, &location)
@@ -208,6 +320,36 @@ func (r *locationResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
+	if isDeletionProtected(location.DeletionProtection, r.deletionProtection) {
+		resp.Diagnostics.AddError("Location is protected against deletion",
+			"Location "+location.Name.ValueString()+" ("+location.ID.ValueString()+") has deletion_protection enabled. "+
+				"Set deletion_protection to false and apply, before deleting the location.")
+		return
+	}
+
+	//Check if the location is still in use by one or more states
+	if !location.ForceDelete.ValueBool() {
+		states, err := r.client.ZeroTrust.GetStates(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading states", "unexpected error: "+err.Error())
+			return
+		}
+
+		var referencedBy []string
+		for _, s := range states {
+			if s.Location == location.ID.ValueString() {
+				referencedBy = append(referencedBy, s.ID+" ("+s.Description+")")
+			}
+		}
+
+		if len(referencedBy) > 0 {
+			resp.Diagnostics.AddError("Location is still in use",
+				"Location "+location.Name.ValueString()+" ("+location.ID.ValueString()+") is referenced by the states ["+strings.Join(referencedBy, ", ")+"]. "+
+					"Remove these states first or set force_delete to true.")
+			return
+		}
+	}
+
 	//Delete location
 	err := r.client.ZeroTrust.DeleteLocationByID(ctx, location.ID.ValueString())
 	if err != nil {
@@ -230,12 +372,46 @@ func resourceModelToLocation(m *locationResourceModel) zerotrust.Location {
 }
 
 // locationToResouceModel maps the zerotrust.location object to the resource model
-func locationToResourceModel(location *zerotrust.Location) locationResourceModel {
+// The Terraform only attributes are taken from the current model (plan or state), the coordinates get the precision
+func locationToResourceModel(location *zerotrust.Location, current *locationResourceModel, precision float64) locationResourceModel {
+	forceDelete := current.ForceDelete
+	if forceDelete.IsNull() || forceDelete.IsUnknown() { //e.g. after import
+		forceDelete = types.BoolValue(false)
+	}
+
 	return locationResourceModel{
-		ID:             types.StringValue(location.ID),
-		Uniqueness_key: types.StringValue(location.UniquenessKey),
-		Name:           types.StringValue(location.Name),
-		Latitude:       types.Float64Value(location.Coords.Latitude),
-		Longitude:      types.Float64Value(location.Coords.Longitude),
+		ID:                 types.StringValue(location.ID),
+		Uniqueness_key:     types.StringValue(location.UniquenessKey),
+		Name:               types.StringValue(location.Name),
+		Latitude:           newCoordinateValue(location.Coords.Latitude, precision),
+		Longitude:          newCoordinateValue(location.Coords.Longitude, precision),
+		Coordinates:        current.Coordinates,
+		DeletionProtection: current.DeletionProtection,
+		ForceDelete:        forceDelete,
 	}
 }
+
+// isInRange returns true when value is a number between -limit and limit
+func isInRange(value, limit float64) bool {
+	return !math.IsNaN(value) && value >= -limit && value <= limit
+}
+
+// parseCoordinates parses coordinates in the format "latitude,longitude"
+func parseCoordinates(coordinates string) (types.Float64, types.Float64, error) {
+	parts := strings.Split(coordinates, ",")
+	if len(parts) != 2 {
+		return types.Float64Null(), types.Float64Null(), fmt.Errorf("coordinates %q should be in the format \"latitude,longitude\"", coordinates)
+	}
+
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return types.Float64Null(), types.Float64Null(), fmt.Errorf("invalid latitude in coordinates %q: %s", coordinates, err.Error())
+	}
+
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return types.Float64Null(), types.Float64Null(), fmt.Errorf("invalid longitude in coordinates %q: %s", coordinates, err.Error())
+	}
+
+	return types.Float64Value(latitude), types.Float64Value(longitude), nil
+}
@@ -2,8 +2,9 @@ package auxo
 
 import (
 	"context"
+	"reflect"
+	"sort"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -17,8 +18,9 @@ import (
 var _ resource.Resource = &measureResource{}
 
 type measureResource struct {
-	client *auxo.Client
-	mutex  *sync.Mutex
+	client   *auxo.Client
+	locks    *keyedMutex
+	readOnly bool
 }
 
 type measureResourceModel struct {
@@ -59,7 +61,8 @@ func (r *measureResource) Configure(_ context.Context, req resource.ConfigureReq
 	// Retrieve the client from the provider config
 	c := req.ProviderData.(*auxoClient)
 	r.client = c.client
-	r.mutex = c.m
+	r.locks = c.locks
+	r.readOnly = c.readOnly
 }
 
 func (r *measureResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -163,8 +166,9 @@ func (r *measureResource) Schema(ctx context.Context, req resource.SchemaRequest
 }
 
 func (r *measureResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	if isReadOnlyBlocked(r.readOnly, "create", "measure", &resp.Diagnostics) {
+		return
+	}
 
 	var plan measureResourceModel
 
@@ -175,19 +179,29 @@ func (r *measureResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	// Get PS and add measures
-	ps, diags := r.resourceModelToCompletePS(&plan, ctx)
+	// Serialize the changes of the protect surface
+	unlock := r.locks.lock(plan.Protectsurface.ValueString())
+	defer unlock()
+
+	measureMap, diags := r.resourceModelToMeasures(&plan, ctx)
 
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	// Create(=update) PS
-	ps, err := r.client.ZeroTrust.UpdateProtectSurface(ctx, *ps)
+	// Get PS, set the measures and update the PS
+	ps, diags := updateProtectSurface(ctx, r.client, protectSurfaceUpdate{
+		id:      plan.Protectsurface.ValueString(),
+		managed: []string{"measures"},
+		modify: func(ps *zerotrust.ProtectSurface) diag.Diagnostics {
+			ps.Measures = measureMap
+			return nil
+		},
+	})
+	resp.Diagnostics.Append(diags...)
 
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating protectsurface", "unexpected error: "+err.Error())
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -216,6 +230,10 @@ func (r *measureResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 	// Get refreshed state from AUXO
 	result, err := r.client.ZeroTrust.GetProtectSurfaceByID(ctx, state.Protectsurface.ValueString())
+	if isNotFoundError(err) { // Protectsurface not found and probably deleted
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Error reading measures", "unexpected error: "+err.Error())
 		return
@@ -235,31 +253,45 @@ func (r *measureResource) Read(ctx context.Context, req resource.ReadRequest, re
 }
 
 func (r *measureResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	if isReadOnlyBlocked(r.readOnly, "update", "measure", &resp.Diagnostics) {
+		return
+	}
 
-	var plan measureResourceModel
+	var plan, state measureResourceModel
 
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Get PS and add measures
-	ps, diags := r.resourceModelToCompletePS(&plan, ctx)
+	// Serialize the changes of the protect surface
+	unlock := r.locks.lock(plan.Protectsurface.ValueString())
+	defer unlock()
+
+	measureMap, diags := r.resourceModelToMeasures(&plan, ctx)
 
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	// Create(=update) PS
-	ps, err := r.client.ZeroTrust.UpdateProtectSurface(ctx, *ps)
+	// Get PS, check the measures did not change since the last refresh, set the measures and update the PS
+	ps, diags := updateProtectSurface(ctx, r.client, protectSurfaceUpdate{
+		id:      plan.Protectsurface.ValueString(),
+		managed: []string{"measures"},
+		drift:   measuresDrift(state.Measures),
+		modify: func(ps *zerotrust.ProtectSurface) diag.Diagnostics {
+			ps.Measures = measureMap
+			return nil
+		},
+	})
+	resp.Diagnostics.Append(diags...)
 
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating protectsurface", "unexpected error: "+err.Error())
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -278,8 +310,9 @@ func (r *measureResource) Update(ctx context.Context, req resource.UpdateRequest
 }
 
 func (r *measureResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	if isReadOnlyBlocked(r.readOnly, "delete", "measure", &resp.Diagnostics) {
+		return
+	}
 
 	// Retrieve values from state
 	var state measureResourceModel
@@ -289,17 +322,21 @@ func (r *measureResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	// Get PS and remove measures
-	ps, err := r.client.ZeroTrust.GetProtectSurfaceByID(ctx, state.Protectsurface.ValueString())
-	ps.Measures = map[string]zerotrust.MeasureState{}
-
-	// Update PS, with deleted measures
-	_, err = r.client.ZeroTrust.CreateProtectSurfaceByObject(ctx, *ps, true)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Error deleting measures", "unexpected error: "+err.Error())
-		return
-	}
+	// Serialize the changes of the protect surface
+	unlock := r.locks.lock(state.Protectsurface.ValueString())
+	defer unlock()
+
+	// Get PS, remove the measures and update the PS, nothing to remove when the protectsurface is already deleted
+	_, diags = updateProtectSurface(ctx, r.client, protectSurfaceUpdate{
+		id:            state.Protectsurface.ValueString(),
+		managed:       []string{"measures"},
+		ignoreMissing: true,
+		modify: func(ps *zerotrust.ProtectSurface) diag.Diagnostics {
+			ps.Measures = map[string]zerotrust.MeasureState{}
+			return nil
+		},
+	})
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r *measureResource) getAvailableMeasures() []string {
@@ -352,16 +389,31 @@ func getMeasuresFromMap(measureMap map[string]zerotrust.MeasureState) map[string
 	return measures
 }
 
-func (r *measureResource) resourceModelToCompletePS(plan *measureResourceModel, ctx context.Context) (*zerotrust.ProtectSurface, diag.Diagnostics) {
-	var diags diag.Diagnostics
+// measuresDrift returns the measures (measures.<name>) on the protectsurface which differ from the prior measures
+func measuresDrift(prior map[string]measure) func(ps *zerotrust.ProtectSurface) []string {
+	return func(ps *zerotrust.ProtectSurface) []string {
+		current := getMeasuresFromMap(ps.Measures)
 
-	psID := plan.Protectsurface.ValueString()
-	ps, err := r.client.ZeroTrust.GetProtectSurfaceByID(ctx, psID)
+		var drifted []string
+		for name, m := range current {
+			if p, ok := prior[name]; !ok || !reflect.DeepEqual(p, m) {
+				drifted = append(drifted, "measures."+name)
+			}
+		}
+		for name := range prior {
+			if _, ok := current[name]; !ok {
+				drifted = append(drifted, "measures."+name)
+			}
+		}
+		sort.Strings(drifted)
 
-	if err != nil {
-		diags.AddError("Error getting protectsurface", "unexpected error: "+err.Error())
-		return nil, diags
+		return drifted
 	}
+}
+
+// resourceModelToMeasures returns the measures of the plan, as set on the protectsurface
+func (r *measureResource) resourceModelToMeasures(plan *measureResourceModel, ctx context.Context) (map[string]zerotrust.MeasureState, diag.Diagnostics) {
+	var diags diag.Diagnostics
 
 	measureMap := make(map[string]zerotrust.MeasureState, 0)
 
@@ -455,7 +507,6 @@ func (r *measureResource) resourceModelToCompletePS(plan *measureResourceModel,
 	if len(measureMap) == 0 {
 		measureMap = nil
 	}
-	ps.Measures = measureMap
 
-	return ps, diags
+	return measureMap, diags
 }
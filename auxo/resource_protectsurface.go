@@ -2,9 +2,11 @@ package auxo
 
 import (
 	"context"
-	"sync"
+	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
@@ -15,17 +17,36 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/on2itsecurity/go-auxo/v2"
+	"github.com/on2itsecurity/go-auxo/v2/crm"
 	"github.com/on2itsecurity/go-auxo/v2/zerotrust"
 )
 
-// var _ resource.ResourceWithModifyPlan = &protectsurfaceResource{}
 var _ resource.Resource = &protectsurfaceResource{}
+var _ resource.ResourceWithValidateConfig = &protectsurfaceResource{}
+var _ resource.ResourceWithModifyPlan = &protectsurfaceResource{}
+
+// onDeletePolicies are the valid values for the on_delete attribute
+var onDeletePolicies = []string{"cascade", "fail", "orphan"}
+
+// customerLabelsModes are the valid values for the customer_labels_mode attribute
+var customerLabelsModes = []string{"additive", "authoritative"}
 
 type protectsurfaceResource struct {
-	client *auxo.Client
-	mutex  *sync.Mutex
+	client             *auxo.Client
+	locks              *keyedMutex
+	deletionProtection bool
+	contacts           *listCache[crm.Contact]
+	defaults           protectsurfaceDefaults
+	readOnly           bool
+}
+
+// protectsurfaceDefaults contains the provider defaults, which are merged into every protectsurface
+type protectsurfaceDefaults struct {
+	customerLabels map[string]string
+	dataTags       []string
+	complianceTags []string
+	socTags        []string
 }
 
 type protectsurfaceResourceModel struct {
@@ -52,6 +73,15 @@ type protectsurfaceResourceModel struct {
 	MaturityStep3         types.Int64  `tfsdk:"maturity_step3"`
 	MaturityStep4         types.Int64  `tfsdk:"maturity_step4"`
 	MaturityStep5         types.Int64  `tfsdk:"maturity_step5"`
+	DeletionProtection    types.Bool   `tfsdk:"deletion_protection"`
+	OnDelete              types.String `tfsdk:"on_delete"`
+	MainContactEmail      types.String `tfsdk:"main_contact_email"`
+	SecurityContactEmail  types.String `tfsdk:"security_contact_email"`
+	CustomerLabelsMode    types.String `tfsdk:"customer_labels_mode"`
+	DataTagsAll           types.Set    `tfsdk:"data_tags_all"`
+	ComplianceTagsAll     types.Set    `tfsdk:"compliance_tags_all"`
+	CustomerLabelsAll     types.Map    `tfsdk:"customer_labels_all"`
+	SOCTagsAll            types.Set    `tfsdk:"soc_tags_all"`
 }
 
 func NewProtectsurfaceResource() resource.Resource {
@@ -70,7 +100,11 @@ func (r *protectsurfaceResource) Configure(_ context.Context, req resource.Confi
 	// Retrieve the client from the provider config
 	c := req.ProviderData.(*auxoClient)
 	r.client = c.client
-	r.mutex = c.m
+	r.locks = c.locks
+	r.deletionProtection = c.deletionProtection
+	r.contacts = c.contacts
+	r.defaults = c.defaults
+	r.readOnly = c.readOnly
 }
 
 func (r *protectsurfaceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -124,6 +158,16 @@ func (r *protectsurfaceResource) Schema(ctx context.Context, req resource.Schema
 				Computed:            true,
 				Default:             stringdefault.StaticString(""),
 			},
+			"main_contact_email": schema.StringAttribute{
+				Description:         "Email of the main contact of the resource protectsurface, resolved to the main_contact ID (conflicts with main_contact)",
+				MarkdownDescription: "Email of the main contact of the resource protectsurface, resolved to the `main_contact` ID (conflicts with `main_contact`)",
+				Optional:            true,
+			},
+			"security_contact_email": schema.StringAttribute{
+				Description:         "Email of the security contact of the resource protectsurface, resolved to the security_contact ID (conflicts with security_contact)",
+				MarkdownDescription: "Email of the security contact of the resource protectsurface, resolved to the `security_contact` ID (conflicts with `security_contact`)",
+				Optional:            true,
+			},
 			"in_control_boundary": schema.BoolAttribute{
 				Description:         "This protect surface is within the 'control boundary'",
 				MarkdownDescription: "This protect surface is within the 'control boundary'",
@@ -204,6 +248,30 @@ func (r *protectsurfaceResource) Schema(ctx context.Context, req resource.Schema
 					setplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"data_tags_all": schema.SetAttribute{
+				Description:         "Data tags of the resource protectsurface, including the provider defaults",
+				MarkdownDescription: "Data tags of the resource protectsurface, including the provider `defaults`",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"compliance_tags_all": schema.SetAttribute{
+				Description:         "Compliance tags of the resource protectsurface, including the provider defaults",
+				MarkdownDescription: "Compliance tags of the resource protectsurface, including the provider `defaults`",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"customer_labels_all": schema.MapAttribute{
+				Description:         "Customer labels of the resource protectsurface, including the provider defaults",
+				MarkdownDescription: "Customer labels of the resource protectsurface, including the provider `defaults`",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"soc_tags_all": schema.SetAttribute{
+				Description:         "SOC tags of the resource protectsurface, including the provider defaults",
+				MarkdownDescription: "SOC tags of the resource protectsurface, including the provider `defaults`",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
 			"allow_flows_from_outside": schema.BoolAttribute{
 				Description:         "Allow flows from outside of the protectsurface coming in",
 				MarkdownDescription: "Allow flows from outside of the protectsurface coming in",
@@ -249,11 +317,141 @@ func (r *protectsurfaceResource) Schema(ctx context.Context, req resource.Schema
 				Computed:            true,
 				Default:             int64default.StaticInt64(1),
 			},
+			"deletion_protection": schema.BoolAttribute{
+				Description:         "Prevent the protectsurface from being deleted, defaults to the provider deletion_protection setting",
+				MarkdownDescription: "Prevent the protectsurface from being deleted, defaults to the provider `deletion_protection` setting",
+				Optional:            true,
+			},
+			"on_delete": schema.StringAttribute{
+				Description:         "What to do with the states of the protectsurface when it is deleted; cascade (delete the states first), fail (refuse when there are states) or orphan (leave the states to the API), defaults to orphan",
+				MarkdownDescription: "What to do with the states of the protectsurface when it is deleted; `cascade` (delete the states first), `fail` (refuse when there are states) or `orphan` (leave the states to the API), defaults to `orphan`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("orphan"),
+			},
+			"customer_labels_mode": schema.StringAttribute{
+				Description:         "How customer_labels are managed; authoritative (labels not in the configuration are removed) or additive (only the labels in the configuration are managed, other labels are preserved), defaults to authoritative",
+				MarkdownDescription: "How `customer_labels` are managed; `authoritative` (labels not in the configuration are removed) or `additive` (only the labels in the configuration are managed, other labels are preserved), defaults to `authoritative`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("authoritative"),
+			},
 		},
 	}
 }
 
+func (r *protectsurfaceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config protectsurfaceResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.OnDelete.IsNull() && !config.OnDelete.IsUnknown() && !sliceContains(onDeletePolicies, config.OnDelete.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("on_delete"), "Invalid on_delete policy",
+			"on_delete ["+config.OnDelete.ValueString()+"] is not valid, use one of ["+strings.Join(onDeletePolicies, ",")+"]")
+	}
+
+	if !config.CustomerLabelsMode.IsNull() && !config.CustomerLabelsMode.IsUnknown() && !sliceContains(customerLabelsModes, config.CustomerLabelsMode.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("customer_labels_mode"), "Invalid customer_labels_mode",
+			"customer_labels_mode ["+config.CustomerLabelsMode.ValueString()+"] is not valid, use one of ["+strings.Join(customerLabelsModes, ",")+"]")
+	}
+
+	if !config.MainContact.IsNull() && !config.MainContactEmail.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("main_contact_email"), "Conflicting contact attributes",
+			"Either main_contact OR main_contact_email can be set")
+	}
+
+	if !config.SecurityContact.IsNull() && !config.SecurityContactEmail.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("security_contact_email"), "Conflicting contact attributes",
+			"Either security_contact OR security_contact_email can be set")
+	}
+}
+
+func (r *protectsurfaceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	//Nothing to do on destroy or when the provider is not configured yet
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var config protectsurfaceResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	//Resolve the contact emails to contact IDs
+	contactEmails := []struct {
+		attribute string
+		email     types.String
+	}{
+		{"main_contact", config.MainContactEmail},
+		{"security_contact", config.SecurityContactEmail},
+	}
+
+	for _, ce := range contactEmails {
+		attribute, email := ce.attribute, ce.email
+		if email.IsNull() {
+			continue
+		}
+
+		if email.IsUnknown() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attribute), types.StringUnknown())...)
+			continue
+		}
+
+		id, d := r.resolveContactEmail(ctx, path.Root(attribute+"_email"), email.ValueString())
+		resp.Diagnostics.Append(d...)
+
+		if d.HasError() {
+			continue
+		}
+
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attribute), types.StringValue(id))...)
+	}
+
+	//Merge the provider defaults into the *_all attributes, so they show up in the plan
+	var plan protectsurfaceResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	merged := plan
+	resp.Diagnostics.Append(r.defaults.merge(ctx, &merged)...)
+
+	//Values which are not configured are only known after apply, unless they consist of the defaults
+	if plan.CustomerLabels.IsUnknown() && (!config.CustomerLabels.IsNull() || len(r.defaults.customerLabels) == 0) {
+		merged.CustomerLabelsAll = types.MapUnknown(types.StringType)
+	}
+	if plan.DataTags.IsUnknown() && (!config.DataTags.IsNull() || len(r.defaults.dataTags) == 0) {
+		merged.DataTagsAll = types.SetUnknown(types.StringType)
+	}
+	if plan.ComplianceTags.IsUnknown() && (!config.ComplianceTags.IsNull() || len(r.defaults.complianceTags) == 0) {
+		merged.ComplianceTagsAll = types.SetUnknown(types.StringType)
+	}
+	if plan.SOCTags.IsUnknown() && (!config.SOCTags.IsNull() || len(r.defaults.socTags) == 0) {
+		merged.SOCTagsAll = types.SetUnknown(types.StringType)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("customer_labels_all"), merged.CustomerLabelsAll)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("data_tags_all"), merged.DataTagsAll)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("compliance_tags_all"), merged.ComplianceTagsAll)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("soc_tags_all"), merged.SOCTagsAll)...)
+}
+
 func (r *protectsurfaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if isReadOnlyBlocked(r.readOnly, "create", "protect surface", &resp.Diagnostics) {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan protectsurfaceResourceModel
 
@@ -281,7 +479,8 @@ func (r *protectsurfaceResource) Create(ctx context.Context, req resource.Create
 	}
 
 	//Map response to schema
-	plan, _ = protectsurfaceToResourceModel(result, ctx)
+	plan, diags = r.readBack(ctx, result, &plan)
+	resp.Diagnostics.Append(diags...)
 
 	// Set state
 	diags = resp.State.Set(ctx, &plan)
@@ -304,9 +503,7 @@ func (r *protectsurfaceResource) Read(ctx context.Context, req resource.ReadRequ
 	// Get refreshed PS from AUXO
 	result, err := r.client.ZeroTrust.GetProtectSurfaceByID(ctx, state.ID.ValueString())
 	if err != nil {
-		apiError := getAPIError(err)
-
-		if apiError.ID == "410" { // Location not found and probably deleted
+		if isNotFoundError(err) { // Location not found and probably deleted
 			resp.State.RemoveResource(ctx)
 			return
 		} else {
@@ -315,8 +512,9 @@ func (r *protectsurfaceResource) Read(ctx context.Context, req resource.ReadRequ
 		}
 	}
 
-	//Overwrite state with refreshed PS
-	state, _ = protectsurfaceToResourceModel(result, ctx)
+	//Overwrite state with refreshed PS, keep the Terraform only attributes
+	state, diags = r.readBack(ctx, result, &state)
+	resp.Diagnostics.Append(diags...)
 
 	//Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -328,6 +526,9 @@ func (r *protectsurfaceResource) Read(ctx context.Context, req resource.ReadRequ
 }
 
 func (r *protectsurfaceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if isReadOnlyBlocked(r.readOnly, "update", "protect surface", &resp.Diagnostics) {
+		return
+	}
 
 	// Retrieve values from plan
 	var plan protectsurfaceResourceModel
@@ -339,6 +540,10 @@ func (r *protectsurfaceResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
+	// Serialize the changes of the protect surface
+	unlock := r.locks.lock(plan.ID.ValueString())
+	defer unlock()
+
 	protectsurface, d := resourceModelToProtectsurface(&plan, ctx, r)
 
 	resp.Diagnostics.Append(d...)
@@ -347,6 +552,24 @@ func (r *protectsurfaceResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
+	//Preserve the labels which are not managed by Terraform
+	if isAdditiveLabels(plan.CustomerLabelsMode) {
+		var state protectsurfaceResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		existing, err := r.client.ZeroTrust.GetProtectSurfaceByID(ctx, plan.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading protect surface", "unexpected error: "+err.Error())
+			return
+		}
+
+		protectsurface.CustomerLabels = mergeCustomerLabels(existing.CustomerLabels, protectsurface.CustomerLabels, state.CustomerLabelsAll)
+	}
+
 	result, err := r.client.ZeroTrust.UpdateProtectSurface(ctx, protectsurface)
 
 	if err != nil {
@@ -354,7 +577,8 @@ func (r *protectsurfaceResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	plan, _ = protectsurfaceToResourceModel(result, ctx)
+	plan, diags = r.readBack(ctx, result, &plan)
+	resp.Diagnostics.Append(diags...)
 
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -365,6 +589,9 @@ func (r *protectsurfaceResource) Update(ctx context.Context, req resource.Update
 }
 
 func (r *protectsurfaceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if isReadOnlyBlocked(r.readOnly, "delete", "protect surface", &resp.Diagnostics) {
+		return
+	}
 
 	// Retrieve values from state
 	var ps protectsurfaceResourceModel
@@ -376,6 +603,56 @@ func (r *protectsurfaceResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
+	if isDeletionProtected(ps.DeletionProtection, r.deletionProtection) {
+		resp.Diagnostics.AddError("Protect surface is protected against deletion",
+			"Protect surface "+ps.Name.ValueString()+" ("+ps.ID.ValueString()+") has deletion_protection enabled. "+
+				"Set deletion_protection to false and apply, before deleting the protect surface.")
+		return
+	}
+
+	// Serialize the changes of the protect surface
+	unlock := r.locks.lock(ps.ID.ValueString())
+	defer unlock()
+
+	//Handle the states of the protectsurface, based on the on_delete policy
+	switch ps.OnDelete.ValueString() {
+	case "cascade", "fail":
+		states, err := r.client.ZeroTrust.GetStatesByProtectSurfaceID(ctx, ps.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading states of protect surface", "unexpected error: "+err.Error())
+			return
+		}
+
+		if len(states) == 0 {
+			break
+		}
+
+		var stateNames []string
+		for _, s := range states {
+			stateNames = append(stateNames, s.ID+" ("+s.Description+")")
+		}
+
+		if ps.OnDelete.ValueString() == "fail" {
+			resp.Diagnostics.AddError("Protect surface still has states",
+				"Protect surface "+ps.Name.ValueString()+" ("+ps.ID.ValueString()+") still has the states ["+strings.Join(stateNames, ", ")+"]. "+
+					"Remove these states first or set on_delete to cascade.")
+			return
+		}
+
+		for i, s := range states {
+			err := r.client.ZeroTrust.DeleteStateByID(ctx, s.ID)
+			if err != nil {
+				resp.Diagnostics.AddError("Error deleting state of protect surface",
+					fmt.Sprintf("Deleted %d of %d states [%s], before failing on state %s, unexpected error: %s",
+						i, len(states), strings.Join(stateNames[:i], ", "), stateNames[i], err.Error()))
+				return
+			}
+		}
+
+		resp.Diagnostics.AddWarning("Deleted states of protect surface",
+			fmt.Sprintf("Deleted %d states of protect surface %s (%s): [%s]", len(states), ps.Name.ValueString(), ps.ID.ValueString(), strings.Join(stateNames, ", ")))
+	}
+
 	err := r.client.ZeroTrust.DeleteProtectSurfaceByID(ctx, ps.ID.ValueString())
 
 	if err != nil {
@@ -385,22 +662,266 @@ func (r *protectsurfaceResource) Delete(ctx context.Context, req resource.Delete
 
 }
 
+// merge sets the *_all attributes of the model to the customer labels and tags merged with the defaults, the values of
+// the model take precedence. Unknown values are merged as not set, since they are only unknown when not configured
+func (d protectsurfaceDefaults) merge(ctx context.Context, m *protectsurfaceResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	labels := make(map[string]string, len(d.customerLabels))
+	for k, v := range d.customerLabels {
+		labels[k] = v
+	}
+
+	if !m.CustomerLabels.IsUnknown() {
+		var resourceLabels map[string]string
+		diags.Append(m.CustomerLabels.ElementsAs(ctx, &resourceLabels, false)...)
+		for k, v := range resourceLabels {
+			labels[k] = v
+		}
+	}
+
+	switch {
+	case len(d.customerLabels) > 0:
+		var dd diag.Diagnostics
+		m.CustomerLabelsAll, dd = types.MapValueFrom(ctx, types.StringType, labels)
+		diags.Append(dd...)
+	case m.CustomerLabels.IsUnknown():
+		m.CustomerLabelsAll = types.MapNull(types.StringType)
+	default:
+		m.CustomerLabelsAll = m.CustomerLabels
+	}
+
+	mergeSet := func(set types.Set, defaults []string) types.Set {
+		if len(defaults) == 0 {
+			if set.IsUnknown() {
+				return types.SetNull(types.StringType)
+			}
+			return set
+		}
+
+		var tags []string
+		if !set.IsUnknown() {
+			diags.Append(set.ElementsAs(ctx, &tags, false)...)
+		}
+		for _, tag := range defaults {
+			if !sliceContains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+
+		result, dd := types.SetValueFrom(ctx, types.StringType, tags)
+		diags.Append(dd...)
+
+		return result
+	}
+
+	m.DataTagsAll = mergeSet(m.DataTags, d.dataTags)
+	m.ComplianceTagsAll = mergeSet(m.ComplianceTags, d.complianceTags)
+	m.SOCTagsAll = mergeSet(m.SOCTags, d.socTags)
+
+	return diags
+}
+
+// removeDefaults removes the defaults from the customer labels and tags of the model, unless they are set in prior, so
+// these attributes keep the configured values, the merged values remain in the *_all attributes
+func (d protectsurfaceDefaults) removeDefaults(ctx context.Context, m *protectsurfaceResourceModel, prior *protectsurfaceResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if len(d.customerLabels) > 0 && !m.CustomerLabels.IsNull() {
+		var labels, priorLabels map[string]string
+		diags.Append(m.CustomerLabels.ElementsAs(ctx, &labels, false)...)
+		if !prior.CustomerLabels.IsUnknown() {
+			diags.Append(prior.CustomerLabels.ElementsAs(ctx, &priorLabels, false)...)
+		}
+
+		for k, v := range d.customerLabels {
+			if _, ok := priorLabels[k]; !ok && labels[k] == v {
+				delete(labels, k)
+			}
+		}
+
+		if len(labels) == 0 && (prior.CustomerLabels.IsNull() || prior.CustomerLabels.IsUnknown()) {
+			m.CustomerLabels = types.MapNull(types.StringType)
+		} else {
+			var dd diag.Diagnostics
+			m.CustomerLabels, dd = types.MapValueFrom(ctx, types.StringType, labels)
+			diags.Append(dd...)
+		}
+	}
+
+	removeTags := func(set types.Set, priorSet types.Set, defaults []string) types.Set {
+		if len(defaults) == 0 || set.IsNull() {
+			return set
+		}
+
+		var tags, priorTags []string
+		diags.Append(set.ElementsAs(ctx, &tags, false)...)
+		if !priorSet.IsUnknown() {
+			diags.Append(priorSet.ElementsAs(ctx, &priorTags, false)...)
+		}
+
+		kept := []string{}
+		for _, tag := range tags {
+			if !sliceContains(defaults, tag) || sliceContains(priorTags, tag) {
+				kept = append(kept, tag)
+			}
+		}
+
+		if len(kept) == 0 && (priorSet.IsNull() || priorSet.IsUnknown()) {
+			return types.SetNull(types.StringType)
+		}
+
+		result, dd := types.SetValueFrom(ctx, types.StringType, kept)
+		diags.Append(dd...)
+
+		return result
+	}
+
+	m.DataTags = removeTags(m.DataTags, prior.DataTags, d.dataTags)
+	m.ComplianceTags = removeTags(m.ComplianceTags, prior.ComplianceTags, d.complianceTags)
+	m.SOCTags = removeTags(m.SOCTags, prior.SOCTags, d.socTags)
+
+	return diags
+}
+
+// readBack maps the protect surface returned by AUXO to the resource model, keeping the Terraform only attributes and
+// the configured customer labels and tags of prior
+func (r *protectsurfaceResource) readBack(ctx context.Context, ps *zerotrust.ProtectSurface, prior *protectsurfaceResourceModel) (protectsurfaceResourceModel, diag.Diagnostics) {
+	m, diags := protectsurfaceToResourceModel(ps, ctx)
+	copyTerraformOnlyAttributes(&m, prior)
+
+	//Hide the labels which are not managed by Terraform
+	if isAdditiveLabels(m.CustomerLabelsMode) {
+		managed := *prior
+		diags.Append(r.defaults.merge(ctx, &managed)...)
+
+		var d diag.Diagnostics
+		m.CustomerLabels, d = filterCustomerLabels(ctx, m.CustomerLabels, prior.CustomerLabels)
+		diags.Append(d...)
+		m.CustomerLabelsAll, d = filterCustomerLabels(ctx, m.CustomerLabelsAll, managed.CustomerLabelsAll)
+		diags.Append(d...)
+	}
+
+	diags.Append(r.defaults.removeDefaults(ctx, &m, prior)...)
+
+	return m, diags
+}
+
+// resolveContactEmail returns the ID of the contact with the given email, using the (cached) CRM contacts
+func (r *protectsurfaceResource) resolveContactEmail(ctx context.Context, attribute path.Path, email string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	contacts, err := r.contacts.get(ctx, r.client.CRM.GetContacts)
+	if err != nil {
+		diags.AddError("Unable to retrieve contacts", err.Error())
+		return "", diags
+	}
+
+	matches := findContactsByEmail(contacts, email)
+
+	switch len(matches) {
+	case 0:
+		diags.AddAttributeError(attribute, "Contact not found", "Unable to find contact with email "+email)
+		return "", diags
+	case 1:
+		return matches[0].ID, diags
+	default:
+		var ids []string
+		for _, c := range matches {
+			ids = append(ids, c.ID)
+		}
+		diags.AddAttributeError(attribute, "Multiple contacts found",
+			"Email "+email+" matches multiple contacts ["+strings.Join(ids, ",")+"], please use the contact ID")
+		return "", diags
+	}
+}
+
+// copyTerraformOnlyAttributes copies the attributes which are not stored in AUXO from src to dst
+func copyTerraformOnlyAttributes(dst *protectsurfaceResourceModel, src *protectsurfaceResourceModel) {
+	dst.DeletionProtection = src.DeletionProtection
+	dst.OnDelete = src.OnDelete
+	dst.MainContactEmail = src.MainContactEmail
+	dst.SecurityContactEmail = src.SecurityContactEmail
+
+	dst.CustomerLabelsMode = src.CustomerLabelsMode
+
+	if dst.OnDelete.IsNull() || dst.OnDelete.IsUnknown() { //e.g. after import
+		dst.OnDelete = types.StringValue("orphan")
+	}
+	if dst.CustomerLabelsMode.IsNull() || dst.CustomerLabelsMode.IsUnknown() {
+		dst.CustomerLabelsMode = types.StringValue("authoritative")
+	}
+}
+
+// isAdditiveLabels returns true when only the configured customer labels are managed
+func isAdditiveLabels(mode types.String) bool {
+	return mode.ValueString() == "additive"
+}
+
+// filterCustomerLabels returns the labels of which the key is in managed, so unmanaged labels are hidden from the diff
+func filterCustomerLabels(ctx context.Context, labels types.Map, managed types.Map) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var all, managedLabels map[string]string
+
+	diags.Append(labels.ElementsAs(ctx, &all, false)...)
+	if !managed.IsUnknown() {
+		diags.Append(managed.ElementsAs(ctx, &managedLabels, false)...)
+	}
+
+	filtered := make(map[string]string)
+	for k, v := range all {
+		if _, ok := managedLabels[k]; ok {
+			filtered[k] = v
+		}
+	}
+
+	result, d := types.MapValueFrom(ctx, types.StringType, filtered)
+	diags.Append(d...)
+
+	return result, diags
+}
+
+// mergeCustomerLabels returns the existing labels with the planned labels applied, previously managed labels which are
+// no longer planned are removed, all other existing labels are preserved
+func mergeCustomerLabels(existing map[string]string, planned map[string]string, previous types.Map) map[string]string {
+	labels := make(map[string]string, len(existing)+len(planned))
+	for k, v := range existing {
+		labels[k] = v
+	}
+
+	for k := range previous.Elements() {
+		if _, ok := planned[k]; !ok {
+			delete(labels, k)
+		}
+	}
+
+	for k, v := range planned {
+		labels[k] = v
+	}
+
+	return labels
+}
+
 // resourceModelToProtectsurface maps the resource model to the zerotrust.protectsurface object
 func resourceModelToProtectsurface(plan *protectsurfaceResourceModel, ctx context.Context, r *protectsurfaceResource) (zerotrust.ProtectSurface, diag.Diagnostics) {
 	var diag diag.Diagnostics
 	var st, dt, ct []string
 
-	if !plan.DataTags.IsNull() {
-		_ = plan.DataTags.ElementsAs(ctx, &dt, false)
+	//Merge the provider defaults, resource values take precedence
+	merged := *plan
+	diag.Append(r.defaults.merge(ctx, &merged)...)
+
+	if !merged.DataTagsAll.IsNull() {
+		_ = merged.DataTagsAll.ElementsAs(ctx, &dt, false)
 	}
-	if !plan.ComplianceTags.IsNull() {
-		_ = plan.ComplianceTags.ElementsAs(ctx, &ct, false)
+	if !merged.ComplianceTagsAll.IsNull() {
+		_ = merged.ComplianceTagsAll.ElementsAs(ctx, &ct, false)
 	}
-	if !plan.SOCTags.IsNull() {
-		_ = plan.SOCTags.ElementsAs(ctx, &st, false)
+	if !merged.SOCTagsAll.IsNull() {
+		_ = merged.SOCTagsAll.ElementsAs(ctx, &st, false)
 	}
 	var cl map[string]string
-	types.Map.ElementsAs(plan.CustomerLabels, ctx, &cl, false)
+	types.Map.ElementsAs(merged.CustomerLabelsAll, ctx, &cl, false)
 
 	//Create the protectsurface object
 	protectsurface := zerotrust.ProtectSurface{
@@ -438,7 +959,7 @@ func resourceModelToProtectsurface(plan *protectsurfaceResourceModel, ctx contex
 func protectsurfaceToResourceModel(ps *zerotrust.ProtectSurface, ctx context.Context) (protectsurfaceResourceModel, diag.Diagnostics) {
 	cl, diag := types.MapValueFrom(ctx, types.StringType, ps.CustomerLabels)
 
-	var st, dt, ct basetypes.SetValue
+	st, dt, ct := types.SetNull(types.StringType), types.SetNull(types.StringType), types.SetNull(types.StringType)
 	if ps.ComplianceTags != nil {
 		ct, _ = types.SetValueFrom(ctx, types.StringType, ps.ComplianceTags)
 	}
@@ -466,6 +987,10 @@ func protectsurfaceToResourceModel(ps *zerotrust.ProtectSurface, ctx context.Con
 		ComplianceTags:        ct,
 		CustomerLabels:        cl,
 		SOCTags:               st,
+		DataTagsAll:           dt,
+		ComplianceTagsAll:     ct,
+		CustomerLabelsAll:     cl,
+		SOCTagsAll:            st,
 		AllowFlowsFromOutside: types.BoolPointerValue(ps.FlowsFromOutside.Allow),
 		AllowFlowsToOutside:   types.BoolPointerValue(ps.FlowsToOutside.Allow),
 		MaturityStep1:         types.Int64Value(int64(ps.Maturity.Step1)),
@@ -0,0 +1,155 @@
+package auxo
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// newTestProtectsurfaceModel returns the configuration of a protect surface, with the customer labels and data tags
+func newTestProtectsurfaceModel(labels types.Map, dataTags types.Set) *protectsurfaceResourceModel {
+	return &protectsurfaceResourceModel{
+		Name:              types.StringValue("web"),
+		Relevance:         types.Int64Value(50),
+		DataTags:          dataTags,
+		ComplianceTags:    types.SetNull(types.StringType),
+		CustomerLabels:    labels,
+		SOCTags:           types.SetNull(types.StringType),
+		DataTagsAll:       types.SetNull(types.StringType),
+		ComplianceTagsAll: types.SetNull(types.StringType),
+		CustomerLabelsAll: types.MapNull(types.StringType),
+		SOCTagsAll:        types.SetNull(types.StringType),
+	}
+}
+
+func TestProtectsurfaceResourceDefaults(t *testing.T) {
+	ctx := context.Background()
+	labels, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"team": "web"})
+	dataTags, _ := types.SetValueFrom(ctx, types.StringType, []string{"PCI"})
+
+	tests := map[string]struct {
+		labels            types.Map
+		dataTags          types.Set
+		wantLabels        map[string]string
+		wantDataTags      []string
+		wantLabelsAll     map[string]string
+		wantDataTagsAll   []string
+		wantComplianceAll []string
+	}{
+		"configured": {
+			labels:            labels,
+			dataTags:          dataTags,
+			wantLabels:        map[string]string{"team": "web"},
+			wantDataTags:      []string{"PCI"},
+			wantLabelsAll:     map[string]string{"cost_center": "1234", "team": "web"},
+			wantDataTagsAll:   []string{"PCI", "PII"},
+			wantComplianceAll: []string{"ISO27001"},
+		},
+		"not configured": {
+			labels:            types.MapNull(types.StringType),
+			dataTags:          types.SetNull(types.StringType),
+			wantLabelsAll:     map[string]string{"cost_center": "1234", "team": "default"},
+			wantDataTagsAll:   []string{"PII"},
+			wantComplianceAll: []string{"ISO27001"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			api, client := newFakeAPI(t)
+
+			r := &protectsurfaceResource{client: client, locks: &keyedMutex{}, defaults: protectsurfaceDefaults{
+				customerLabels: map[string]string{"cost_center": "1234", "team": "default"},
+				dataTags:       []string{"PII"},
+				complianceTags: []string{"ISO27001"},
+			}}
+
+			configModel := newTestProtectsurfaceModel(tt.labels, tt.dataTags)
+			config := newTestState(t, r, configModel)
+
+			//Optional and computed attributes which are not configured are unknown in the plan
+			planModel := *configModel
+			planModel.ID = types.StringUnknown()
+			if tt.labels.IsNull() {
+				planModel.CustomerLabels = types.MapUnknown(types.StringType)
+			}
+			if tt.dataTags.IsNull() {
+				planModel.DataTags = types.SetUnknown(types.StringType)
+			}
+			planModel.ComplianceTags = types.SetUnknown(types.StringType)
+			planModel.SOCTags = types.SetUnknown(types.StringType)
+			plan := newTestState(t, r, &planModel)
+
+			modifyResp := resource.ModifyPlanResponse{Plan: tfsdk.Plan(plan)}
+			r.ModifyPlan(ctx, resource.ModifyPlanRequest{
+				Config: tfsdk.Config(config),
+				Plan:   tfsdk.Plan(plan),
+			}, &modifyResp)
+			if modifyResp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error %v", modifyResp.Diagnostics)
+			}
+
+			var planned protectsurfaceResourceModel
+			modifyResp.Plan.Get(ctx, &planned)
+			if !planned.CustomerLabels.Equal(planModel.CustomerLabels) || !planned.DataTags.Equal(planModel.DataTags) {
+				t.Errorf("expected the planned labels and tags as configured, got %v and %v", planned.CustomerLabels, planned.DataTags)
+			}
+			assertProtectsurfaceAll(t, "planned", planned, tt.wantLabelsAll, tt.wantDataTagsAll, tt.wantComplianceAll)
+
+			createResp := resource.CreateResponse{State: newTestState(t, r, configModel)}
+			r.Create(ctx, resource.CreateRequest{Plan: modifyResp.Plan}, &createResp)
+			if createResp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error %v", createResp.Diagnostics)
+			}
+
+			var state protectsurfaceResourceModel
+			createResp.State.Get(ctx, &state)
+
+			var stateLabels map[string]string
+			var stateDataTags []string
+			state.CustomerLabels.ElementsAs(ctx, &stateLabels, false)
+			state.DataTags.ElementsAs(ctx, &stateDataTags, false)
+			if !reflect.DeepEqual(stateLabels, tt.wantLabels) || !reflect.DeepEqual(stateDataTags, tt.wantDataTags) {
+				t.Errorf("expected the labels %v and tags %v as configured, got %v and %v", tt.wantLabels, tt.wantDataTags, stateLabels, stateDataTags)
+			}
+			assertProtectsurfaceAll(t, "created", state, tt.wantLabelsAll, tt.wantDataTagsAll, tt.wantComplianceAll)
+
+			readResp := resource.ReadResponse{State: createResp.State}
+			r.Read(ctx, resource.ReadRequest{State: createResp.State}, &readResp)
+			if readResp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error %v", readResp.Diagnostics)
+			}
+			if !readResp.State.Raw.Equal(createResp.State.Raw) {
+				t.Errorf("expected no changes after read, got %v", readResp.State.Raw)
+			}
+
+			ps := api.protectsurfaces[state.ID.ValueString()]
+			if ps == nil || !reflect.DeepEqual(ps.CustomerLabels, tt.wantLabelsAll) {
+				t.Errorf("expected the merged labels %v to be sent, got %+v", tt.wantLabelsAll, ps)
+			}
+		})
+	}
+}
+
+// assertProtectsurfaceAll checks the *_all attributes of the model
+func assertProtectsurfaceAll(t *testing.T, stage string, m protectsurfaceResourceModel, labels map[string]string, dataTags []string, complianceTags []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	var gotLabels map[string]string
+	var gotDataTags, gotComplianceTags []string
+	m.CustomerLabelsAll.ElementsAs(ctx, &gotLabels, false)
+	m.DataTagsAll.ElementsAs(ctx, &gotDataTags, false)
+	m.ComplianceTagsAll.ElementsAs(ctx, &gotComplianceTags, false)
+	sort.Strings(gotDataTags)
+
+	if !reflect.DeepEqual(gotLabels, labels) || !reflect.DeepEqual(gotDataTags, dataTags) || !reflect.DeepEqual(gotComplianceTags, complianceTags) {
+		t.Errorf("expected the %s labels %v, data tags %v and compliance tags %v, got %v, %v and %v",
+			stage, labels, dataTags, complianceTags, gotLabels, gotDataTags, gotComplianceTags)
+	}
+}
@@ -2,8 +2,10 @@ package auxo
 
 import (
 	"context"
-	"sync"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -13,14 +15,19 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/on2itsecurity/go-auxo/v2"
+	"github.com/on2itsecurity/go-auxo/v2/asset"
 	"github.com/on2itsecurity/go-auxo/v2/zerotrust"
 )
 
 var _ resource.Resource = &stateResource{}
+var _ resource.ResourceWithValidateConfig = &stateResource{}
+var _ resource.ResourceWithModifyPlan = &stateResource{}
 
 type stateResource struct {
-	client *auxo.Client
-	mutex  *sync.Mutex
+	client   *auxo.Client
+	locks    *keyedMutex
+	assets   *listCache[asset.AssetItem]
+	readOnly bool
 }
 
 type stateResourceModel struct {
@@ -31,6 +38,7 @@ type stateResourceModel struct {
 	Location       types.String `tfsdk:"location_id"`
 	ContentType    types.String `tfsdk:"content_type"`
 	ExistsOnAssets types.Set    `tfsdk:"exists_on_assets"`
+	ExistsOnNames  types.Set    `tfsdk:"exists_on_asset_names"`
 	Maintainer     types.String `tfsdk:"maintainer"`
 	Content        types.Set    `tfsdk:"content"`
 }
@@ -51,7 +59,9 @@ func (r *stateResource) Configure(_ context.Context, req resource.ConfigureReque
 	// Retrieve the client from the provider config
 	c := req.ProviderData.(*auxoClient)
 	r.client = c.client
-	r.mutex = c.m
+	r.locks = c.locks
+	r.assets = c.assets
+	r.readOnly = c.readOnly
 }
 
 func (r *stateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -111,6 +121,12 @@ func (r *stateResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					setplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"exists_on_asset_names": schema.SetAttribute{
+				Description:         "Contains asset names which could match this state, resolved to exists_on_assets (conflicts with exists_on_assets)",
+				MarkdownDescription: "Contains asset names which could match this state, resolved to `exists_on_assets` (conflicts with `exists_on_assets`)",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
 			"maintainer": schema.StringAttribute{
 				Description:         "Maintainer of the state either api or portal_manual",
 				MarkdownDescription: "Maintainer of the state either api or portal_manual",
@@ -128,7 +144,119 @@ func (r *stateResource) Schema(ctx context.Context, req resource.SchemaRequest,
 	}
 }
 
+func (r *stateResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config stateResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.ExistsOnAssets.IsNull() && !config.ExistsOnNames.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("exists_on_asset_names"), "Conflicting asset attributes",
+			"Either exists_on_assets OR exists_on_asset_names can be set")
+	}
+
+	//Validate the content, with the same normalization as the normalize_content function
+	if config.ContentType.IsUnknown() || config.Content.IsUnknown() || config.Content.IsNull() {
+		return
+	}
+
+	var content []types.String
+	resp.Diagnostics.Append(config.Content.ElementsAs(ctx, &content, false)...)
+
+	normalized := map[string]string{}
+	for _, c := range content {
+		if c.IsUnknown() || c.IsNull() {
+			continue
+		}
+
+		n, err := normalizeContent(stateContentType(config.ContentType), c.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("content"), "Invalid content", "Content "+err.Error())
+			continue
+		}
+
+		if other, ok := normalized[n]; ok {
+			resp.Diagnostics.AddAttributeError(path.Root("content"), "Duplicate content",
+				"Content ["+other+"] and ["+c.ValueString()+"] are both normalized as ["+n+"], remove one of them")
+		}
+		normalized[n] = c.ValueString()
+	}
+}
+
+func (r *stateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	//Nothing to do on destroy or when the provider is not configured yet
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var config stateResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || config.ExistsOnNames.IsNull() {
+		return
+	}
+
+	if config.ExistsOnNames.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("exists_on_assets"), types.SetUnknown(types.StringType))...)
+		return
+	}
+
+	//Resolve the asset names to asset IDs
+	var names []types.String
+	resp.Diagnostics.Append(config.ExistsOnNames.ElementsAs(ctx, &names, false)...)
+
+	assets, err := r.assets.get(ctx, r.client.Asset.GetAssets)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to retrieve assets", err.Error())
+		return
+	}
+
+	ids := []string{}
+	for _, name := range names {
+		if name.IsUnknown() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("exists_on_assets"), types.SetUnknown(types.StringType))...)
+			return
+		}
+
+		var matches []string
+		for _, a := range assets {
+			if a.Name == name.ValueString() {
+				matches = append(matches, a.ID)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			resp.Diagnostics.AddAttributeError(path.Root("exists_on_asset_names"), "Asset not found",
+				"Unable to find asset with name "+name.ValueString())
+		case 1:
+			ids = append(ids, matches[0])
+		default:
+			resp.Diagnostics.AddAttributeError(path.Root("exists_on_asset_names"), "Duplicate name on backend, please use exists_on_assets",
+				"Asset name "+name.ValueString()+" matches multiple assets ["+strings.Join(matches, ",")+"], please use exists_on_assets")
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	existsOnAssets, diags := types.SetValueFrom(ctx, types.StringType, ids)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("exists_on_assets"), existsOnAssets)...)
+}
+
 func (r *stateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if isReadOnlyBlocked(r.readOnly, "create", "state", &resp.Diagnostics) {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan stateResourceModel
 
@@ -139,6 +267,10 @@ func (r *stateResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	// Serialize the changes of the protect surface
+	unlock := r.locks.lock(plan.Protectsurface.ValueString())
+	defer unlock()
+
 	// Create state (object)
 	state := resourceModelToState(&plan, ctx)
 
@@ -151,7 +283,12 @@ func (r *stateResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	// Map resonse to schema
+	existsOnNames, content := plan.ExistsOnNames, plan.Content
 	plan = stateToResourceModel(result, ctx)
+	plan.ExistsOnNames = existsOnNames
+
+	plan.Content, diags = keepConfiguredContent(ctx, plan.ContentType, plan.Content, content)
+	resp.Diagnostics.Append(diags...)
 
 	// Set state
 	diags = resp.State.Set(ctx, &plan)
@@ -174,9 +311,7 @@ func (r *stateResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	// Get refreshed state from AUXO
 	result, err := r.client.ZeroTrust.GetStateByID(ctx, state.ID.ValueString())
 	if err != nil {
-		apiError := getAPIError(err)
-
-		if apiError.ID == "410" { // Location not found and probably deleted
+		if isNotFoundError(err) { // Location not found and probably deleted
 			resp.State.RemoveResource(ctx)
 			return
 		} else {
@@ -185,9 +320,20 @@ func (r *stateResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		}
 	}
 
-	//Overwrite state with refreshed state
+	//Overwrite state with refreshed state, keep the configured form of the content
+	existsOnNames, content := state.ExistsOnNames, state.Content
 	state = stateToResourceModel(result, ctx)
 
+	state.Content, diags = keepConfiguredContent(ctx, state.ContentType, state.Content, content)
+	resp.Diagnostics.Append(diags...)
+
+	//Refresh the asset names, so renamed assets show up as drift
+	if !existsOnNames.IsNull() {
+		existsOnNames, diags = r.getAssetNames(ctx, result.ExistsOnAssetIDs)
+		resp.Diagnostics.Append(diags...)
+	}
+	state.ExistsOnNames = existsOnNames
+
 	//Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -197,16 +343,26 @@ func (r *stateResource) Read(ctx context.Context, req resource.ReadRequest, resp
 
 }
 func (r *stateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	//Retrieve values from plan
-	var plan stateResourceModel
+	if isReadOnlyBlocked(r.readOnly, "update", "state", &resp.Diagnostics) {
+		return
+	}
+
+	//Retrieve values from plan and state
+	var plan, prior stateResourceModel
 
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &prior)
+	resp.Diagnostics.Append(diags...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// Serialize the changes of the protect surface, the state can move to another protect surface
+	unlock := r.locks.lockAll(prior.Protectsurface.ValueString(), plan.Protectsurface.ValueString())
+	defer unlock()
+
 	// Create state (object)
 	state := resourceModelToState(&plan, ctx)
 
@@ -218,7 +374,12 @@ func (r *stateResource) Update(ctx context.Context, req resource.UpdateRequest,
 	}
 
 	// Map resonse to schema
+	existsOnNames, content := plan.ExistsOnNames, plan.Content
 	plan = stateToResourceModel(result, ctx)
+	plan.ExistsOnNames = existsOnNames
+
+	plan.Content, diags = keepConfiguredContent(ctx, plan.ContentType, plan.Content, content)
+	resp.Diagnostics.Append(diags...)
 
 	// Set state
 	diags = resp.State.Set(ctx, &plan)
@@ -231,6 +392,10 @@ func (r *stateResource) Update(ctx context.Context, req resource.UpdateRequest,
 }
 
 func (r *stateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if isReadOnlyBlocked(r.readOnly, "delete", "state", &resp.Diagnostics) {
+		return
+	}
+
 	// Retrieve values from state
 	var state stateResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -239,6 +404,10 @@ func (r *stateResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	// Serialize the changes of the protect surface
+	unlock := r.locks.lock(state.Protectsurface.ValueString())
+	defer unlock()
+
 	// Delete state
 	err := r.client.ZeroTrust.DeleteStateByID(ctx, state.ID.ValueString())
 	if err != nil {
@@ -247,6 +416,26 @@ func (r *stateResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 }
 
+// getAssetNames returns the (current) names of the given asset IDs, unknown IDs are skipped
+func (r *stateResource) getAssetNames(ctx context.Context, ids []string) (types.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	assets, err := r.assets.get(ctx, r.client.Asset.GetAssets)
+	if err != nil {
+		diags.AddError("Unable to retrieve assets", err.Error())
+		return types.SetNull(types.StringType), diags
+	}
+
+	names := []string{}
+	for _, a := range assets {
+		if sliceContains(ids, a.ID) {
+			names = append(names, a.Name)
+		}
+	}
+
+	return types.SetValueFrom(ctx, types.StringType, names)
+}
+
 // resourceModelToState maps the resource model to the zerotrust.state object
 func resourceModelToState(m *stateResourceModel, ctx context.Context) zerotrust.State {
 	var existsOnAssets, content []string
@@ -259,6 +448,13 @@ func resourceModelToState(m *stateResourceModel, ctx context.Context) zerotrust.
 		_ = m.Content.ElementsAs(ctx, &content, false)
 	}
 
+	//Send the normalized content, invalid content is already reported by ValidateConfig
+	for i, c := range content {
+		if normalized, err := normalizeContent(stateContentType(m.ContentType), c); err == nil {
+			content[i] = normalized
+		}
+	}
+
 	state := zerotrust.State{
 		ID:               m.ID.ValueString(),
 		UniquenessKey:    m.Uniqueness_key.ValueString(),
@@ -296,3 +492,49 @@ func stateToResourceModel(state *zerotrust.State, ctx context.Context) stateReso
 		Content:        content,
 	}
 }
+
+// stateContentType returns the content type of the state, which defaults to ipv4
+func stateContentType(contentType types.String) string {
+	if contentType.IsNull() || contentType.IsUnknown() {
+		return "ipv4"
+	}
+
+	return contentType.ValueString()
+}
+
+// keepConfiguredContent returns the content, in which the values that equal a configured value after normalization are
+// replaced by the configured value, so the normalization of the content does not show up as a difference
+func keepConfiguredContent(ctx context.Context, contentType types.String, content types.Set, configured types.Set) (types.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if content.IsNull() || configured.IsNull() || configured.IsUnknown() {
+		return content, diags
+	}
+
+	var values, configuredValues []string
+	diags.Append(content.ElementsAs(ctx, &values, false)...)
+	diags.Append(configured.ElementsAs(ctx, &configuredValues, false)...)
+
+	configuredForm := map[string]string{}
+	for _, c := range configuredValues {
+		if normalized, err := normalizeContent(stateContentType(contentType), c); err == nil {
+			configuredForm[normalized] = c
+		}
+	}
+
+	for i, v := range values {
+		normalized, err := normalizeContent(stateContentType(contentType), v)
+		if err != nil {
+			continue
+		}
+
+		if c, ok := configuredForm[normalized]; ok {
+			values[i] = c
+		}
+	}
+
+	result, d := types.SetValueFrom(ctx, types.StringType, values)
+	diags.Append(d...)
+
+	return result, diags
+}
@@ -3,8 +3,9 @@ package auxo
 import (
 	"context"
 	"fmt"
-	"sync"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -16,10 +17,16 @@ import (
 )
 
 var _ resource.Resource = &transactionflowResource{}
+var _ resource.ResourceWithModifyPlan = &transactionflowResource{}
+
+// flowFields are the (JSON) fields of the protectsurface managed by the transactionflow resource
+var flowFields = []string{"flows_from_other_ps", "flows_to_other_ps"}
 
 type transactionflowResource struct {
-	client *auxo.Client
-	mutex  *sync.Mutex
+	client          *auxo.Client
+	locks           *keyedMutex
+	protectsurfaces *listCache[zerotrust.ProtectSurface]
+	readOnly        bool
 }
 
 type transactionflowResourceModel struct {
@@ -28,6 +35,8 @@ type transactionflowResourceModel struct {
 	Incoming_protectsurfaces_block types.Set    `tfsdk:"incoming_protectsurfaces_block"`
 	Outgoing_protectsurfaces_allow types.Set    `tfsdk:"outgoing_protectsurfaces_allow"`
 	Outgoing_protectsurfaces_block types.Set    `tfsdk:"outgoing_protectsurfaces_block"`
+	Incoming_protectsurfaces_unset types.Set    `tfsdk:"incoming_protectsurfaces_unset"`
+	Outgoing_protectsurfaces_unset types.Set    `tfsdk:"outgoing_protectsurfaces_unset"`
 }
 
 type flows struct {
@@ -35,6 +44,8 @@ type flows struct {
 	incomingPSBlock []basetypes.StringValue
 	outgoingPSAllow []basetypes.StringValue
 	outgoingPSBlock []basetypes.StringValue
+	incomingPSUnset []basetypes.StringValue
+	outgoingPSUnset []basetypes.StringValue
 }
 
 func NewTransactionflowResource() resource.Resource {
@@ -53,7 +64,9 @@ func (r *transactionflowResource) Configure(_ context.Context, req resource.Conf
 	// Retrieve the client from the provider config
 	c := req.ProviderData.(*auxoClient)
 	r.client = c.client
-	r.mutex = c.m
+	r.locks = c.locks
+	r.protectsurfaces = c.protectsurfaces
+	r.readOnly = c.readOnly
 }
 
 func (r *transactionflowResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -106,13 +119,35 @@ func (r *transactionflowResource) Schema(ctx context.Context, req resource.Schem
 					setplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"incoming_protectsurfaces_unset": schema.SetAttribute{
+				Description:         "The IDs of the protectsurface with an incoming flow to this protectsurface, which is neither allowed nor blocked (yet)",
+				MarkdownDescription: "The IDs of the protectsurface with an incoming flow to this protectsurface, which is neither allowed nor blocked (yet)",
+				Optional:            true,
+				Computed:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"outgoing_protectsurfaces_unset": schema.SetAttribute{
+				Description:         "The IDs of the protectsurface with an outgoing flow from this protectsurface, which is neither allowed nor blocked (yet)",
+				MarkdownDescription: "The IDs of the protectsurface with an outgoing flow from this protectsurface, which is neither allowed nor blocked (yet)",
+				Optional:            true,
+				Computed:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
 
-func (r *transactionflowResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+func (r *transactionflowResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	//Nothing to do on destroy or when the provider is not configured yet
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
 
 	var plan transactionflowResourceModel
 
@@ -123,31 +158,129 @@ func (r *transactionflowResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
-	// create transactionflow
-	psID := plan.Protectsurface.ValueString()
-	ps, err := r.client.ZeroTrust.GetProtectSurfaceByID(ctx, psID)
+	var config transactionflowResourceModel
+
+	diags = req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	//Flows which are moved to a configured set, are removed from the sets which are not configured (and keep the state)
+	changed, diags := pruneUnconfiguredFlows(ctx, &config, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if changed {
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	protectsurfaces, err := r.protectsurfaces.get(ctx, r.client.ZeroTrust.GetProtectSurfaces)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating transactionflow", "unexpected error: "+err.Error())
+		resp.Diagnostics.AddError("Unable to retrieve protect surfaces", err.Error())
 		return
 	}
 
+	known := make(map[string]*zerotrust.ProtectSurface, len(protectsurfaces))
+	for _, ps := range protectsurfaces {
+		known[ps.ID] = ps
+	}
+
+	for _, attribute := range []struct {
+		name string
+		set  types.Set
+	}{
+		{"incoming_protectsurfaces_allow", plan.Incoming_protectsurfaces_allow},
+		{"incoming_protectsurfaces_block", plan.Incoming_protectsurfaces_block},
+		{"incoming_protectsurfaces_unset", plan.Incoming_protectsurfaces_unset},
+		{"outgoing_protectsurfaces_allow", plan.Outgoing_protectsurfaces_allow},
+		{"outgoing_protectsurfaces_block", plan.Outgoing_protectsurfaces_block},
+		{"outgoing_protectsurfaces_unset", plan.Outgoing_protectsurfaces_unset},
+	} {
+		var ids []types.String
+		resp.Diagnostics.Append(attribute.set.ElementsAs(ctx, &ids, false)...)
+
+		for _, id := range ids {
+			//Protect surfaces which are created in the same apply are unknown during plan
+			if id.IsUnknown() || id.IsNull() {
+				continue
+			}
+
+			if !plan.Protectsurface.IsUnknown() && id.ValueString() == plan.Protectsurface.ValueString() {
+				resp.Diagnostics.AddAttributeError(path.Root(attribute.name), "Flow to the protectsurface itself",
+					"Protectsurface "+id.ValueString()+" can not have a flow to or from itself")
+				continue
+			}
+
+			ps, ok := known[id.ValueString()]
+			if !ok {
+				//The cache is loaded once, the protectsurface can be created after it was loaded
+				ps, err = r.client.ZeroTrust.GetProtectSurfaceByID(ctx, id.ValueString())
+				if isNotFoundError(err) {
+					resp.Diagnostics.AddAttributeError(path.Root(attribute.name), "Protectsurface not found",
+						"Unable to find protectsurface with ID "+id.ValueString())
+					continue
+				}
+				if err != nil {
+					resp.Diagnostics.AddError("Unable to retrieve protectsurface", err.Error())
+					return
+				}
+				known[ps.ID] = ps
+			}
+
+			if !ps.InZeroTrustFocus {
+				resp.Diagnostics.AddAttributeWarning(path.Root(attribute.name), "Protectsurface not in zero trust focus",
+					"Protectsurface "+ps.Name+" ("+ps.ID+") is not in the zero trust focus, the flow is not measured or reported")
+			}
+		}
+	}
+}
+
+func (r *transactionflowResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if isReadOnlyBlocked(r.readOnly, "create", "transaction flow", &resp.Diagnostics) {
+		return
+	}
+
+	var plan transactionflowResourceModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Serialize the changes of the protect surface
+	unlock := r.locks.lock(plan.Protectsurface.ValueString())
+	defer unlock()
+
 	var f flows
 	_ = plan.Incoming_protectsurfaces_allow.ElementsAs(ctx, &f.incomingPSAllow, false)
 	_ = plan.Incoming_protectsurfaces_block.ElementsAs(ctx, &f.incomingPSBlock, false)
 	_ = plan.Outgoing_protectsurfaces_allow.ElementsAs(ctx, &f.outgoingPSAllow, false)
 	_ = plan.Outgoing_protectsurfaces_block.ElementsAs(ctx, &f.outgoingPSBlock, false)
+	_ = plan.Incoming_protectsurfaces_unset.ElementsAs(ctx, &f.incomingPSUnset, false)
+	_ = plan.Outgoing_protectsurfaces_unset.ElementsAs(ctx, &f.outgoingPSUnset, false)
+
+	// create transactionflow, get PS, set the flows and update the PS
+	ps, diags := updateProtectSurface(ctx, r.client, protectSurfaceUpdate{
+		id:      plan.Protectsurface.ValueString(),
+		managed: flowFields,
+		modify: func(ps *zerotrust.ProtectSurface) diag.Diagnostics {
+			var diags diag.Diagnostics
+			if _, err := setFlowsOnPS(ps, f); err != nil {
+				diags.AddError("Error creating transactionflow", err.Error())
+			}
+			return diags
+		},
+	})
+	resp.Diagnostics.Append(diags...)
 
-	ps, err = setFlowsOnPS(ps, f)
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating transactionflow", err.Error())
-		return
-	}
-
-	ps, err = r.client.ZeroTrust.CreateProtectSurfaceByObject(ctx, *ps, true)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating transactionflow", "unexpected error: "+err.Error())
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -158,6 +291,8 @@ func (r *transactionflowResource) Create(ctx context.Context, req resource.Creat
 	plan.Incoming_protectsurfaces_block, _ = types.SetValueFrom(ctx, types.StringType, f.incomingPSBlock)
 	plan.Outgoing_protectsurfaces_allow, _ = types.SetValueFrom(ctx, types.StringType, f.outgoingPSAllow)
 	plan.Outgoing_protectsurfaces_block, _ = types.SetValueFrom(ctx, types.StringType, f.outgoingPSBlock)
+	plan.Incoming_protectsurfaces_unset, _ = types.SetValueFrom(ctx, types.StringType, f.incomingPSUnset)
+	plan.Outgoing_protectsurfaces_unset, _ = types.SetValueFrom(ctx, types.StringType, f.outgoingPSUnset)
 
 	// Set state
 	diags = resp.State.Set(ctx, &plan)
@@ -179,6 +314,10 @@ func (r *transactionflowResource) Read(ctx context.Context, req resource.ReadReq
 
 	// Get refreshed state from AUXO
 	result, err := r.client.ZeroTrust.GetProtectSurfaceByID(ctx, state.Protectsurface.ValueString())
+	if isNotFoundError(err) { // Protectsurface not found and probably deleted
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Error reading transactionflows", "unexpected error: "+err.Error())
 		return
@@ -191,6 +330,8 @@ func (r *transactionflowResource) Read(ctx context.Context, req resource.ReadReq
 	state.Incoming_protectsurfaces_block, _ = types.SetValueFrom(ctx, types.StringType, f.incomingPSBlock)
 	state.Outgoing_protectsurfaces_allow, _ = types.SetValueFrom(ctx, types.StringType, f.outgoingPSAllow)
 	state.Outgoing_protectsurfaces_block, _ = types.SetValueFrom(ctx, types.StringType, f.outgoingPSBlock)
+	state.Incoming_protectsurfaces_unset, _ = types.SetValueFrom(ctx, types.StringType, f.incomingPSUnset)
+	state.Outgoing_protectsurfaces_unset, _ = types.SetValueFrom(ctx, types.StringType, f.outgoingPSUnset)
 
 	//Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -202,44 +343,50 @@ func (r *transactionflowResource) Read(ctx context.Context, req resource.ReadReq
 }
 
 func (r *transactionflowResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	if isReadOnlyBlocked(r.readOnly, "update", "transaction flow", &resp.Diagnostics) {
+		return
+	}
 
-	//Retrieve values from plan
-	var plan transactionflowResourceModel
+	//Retrieve values from plan and state
+	var plan, state transactionflowResourceModel
 
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// create transactionflow
-	psID := plan.Protectsurface.ValueString()
-	ps, err := r.client.ZeroTrust.GetProtectSurfaceByID(ctx, psID)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating transactionflow", "unexpected error: "+err.Error())
-		return
-	}
+	// Serialize the changes of the protect surface
+	unlock := r.locks.lock(plan.Protectsurface.ValueString())
+	defer unlock()
 
 	var f flows
 	_ = plan.Incoming_protectsurfaces_allow.ElementsAs(ctx, &f.incomingPSAllow, false)
 	_ = plan.Incoming_protectsurfaces_block.ElementsAs(ctx, &f.incomingPSBlock, false)
 	_ = plan.Outgoing_protectsurfaces_allow.ElementsAs(ctx, &f.outgoingPSAllow, false)
 	_ = plan.Outgoing_protectsurfaces_block.ElementsAs(ctx, &f.outgoingPSBlock, false)
+	_ = plan.Incoming_protectsurfaces_unset.ElementsAs(ctx, &f.incomingPSUnset, false)
+	_ = plan.Outgoing_protectsurfaces_unset.ElementsAs(ctx, &f.outgoingPSUnset, false)
+
+	// get PS, check the flows did not change since the last refresh, set the flows and update the PS
+	ps, diags := updateProtectSurface(ctx, r.client, protectSurfaceUpdate{
+		id:      plan.Protectsurface.ValueString(),
+		managed: flowFields,
+		drift:   flowsDrift(ctx, &state),
+		modify: func(ps *zerotrust.ProtectSurface) diag.Diagnostics {
+			var diags diag.Diagnostics
+			if _, err := setFlowsOnPS(ps, f); err != nil {
+				diags.AddError("Error updating transactionflow", err.Error())
+			}
+			return diags
+		},
+	})
+	resp.Diagnostics.Append(diags...)
 
-	ps, err = setFlowsOnPS(ps, f)
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating transactionflow", err.Error())
-		return
-	}
-
-	ps, err = r.client.ZeroTrust.CreateProtectSurfaceByObject(ctx, *ps, true)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating transactionflow", "unexpected error: "+err.Error())
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -251,6 +398,8 @@ func (r *transactionflowResource) Update(ctx context.Context, req resource.Updat
 	plan.Incoming_protectsurfaces_block, _ = types.SetValueFrom(ctx, types.StringType, f.incomingPSBlock)
 	plan.Outgoing_protectsurfaces_allow, _ = types.SetValueFrom(ctx, types.StringType, f.outgoingPSAllow)
 	plan.Outgoing_protectsurfaces_block, _ = types.SetValueFrom(ctx, types.StringType, f.outgoingPSBlock)
+	plan.Incoming_protectsurfaces_unset, _ = types.SetValueFrom(ctx, types.StringType, f.incomingPSUnset)
+	plan.Outgoing_protectsurfaces_unset, _ = types.SetValueFrom(ctx, types.StringType, f.outgoingPSUnset)
 
 	// Set state
 	diags = resp.State.Set(ctx, &plan)
@@ -263,8 +412,9 @@ func (r *transactionflowResource) Update(ctx context.Context, req resource.Updat
 }
 
 func (r *transactionflowResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	if isReadOnlyBlocked(r.readOnly, "delete", "transaction flow", &resp.Diagnostics) {
+		return
+	}
 
 	// Retrieve values from state
 	var state transactionflowResourceModel
@@ -274,26 +424,26 @@ func (r *transactionflowResource) Delete(ctx context.Context, req resource.Delet
 		return
 	}
 
-	// Get PS and remove flows
-	ps, err := r.client.ZeroTrust.GetProtectSurfaceByID(ctx, state.Protectsurface.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Error finding protect surface", "unexpected error: "+err.Error())
-		return
-	}
-
-	ps.FlowsFromOtherPS = map[string]zerotrust.Flow{}
-	ps.FlowsToOtherPS = map[string]zerotrust.Flow{}
-
-	// Update PS, with deleted flows
-	_, err = r.client.ZeroTrust.CreateProtectSurfaceByObject(ctx, *ps, true)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Error deleting state", "unexpected error: "+err.Error())
-		return
-	}
+	// Serialize the changes of the protect surface
+	unlock := r.locks.lock(state.Protectsurface.ValueString())
+	defer unlock()
+
+	// Get PS, remove the flows and update the PS, nothing to remove when the protectsurface is already deleted
+	_, diags = updateProtectSurface(ctx, r.client, protectSurfaceUpdate{
+		id:            state.Protectsurface.ValueString(),
+		managed:       flowFields,
+		ignoreMissing: true,
+		modify: func(ps *zerotrust.ProtectSurface) diag.Diagnostics {
+			ps.FlowsFromOtherPS = map[string]zerotrust.Flow{}
+			ps.FlowsToOtherPS = map[string]zerotrust.Flow{}
+			return nil
+		},
+	})
+	resp.Diagnostics.Append(diags...)
 }
 
 // readFlowsFromPS, get a ProtectSurface and return a flows struct, which can be used to map directly on plan & state
+// Flows without allow (neither allowed nor blocked) are returned as unset.
 func readFlowsFromPS(ps *zerotrust.ProtectSurface) flows {
 	var f flows
 	//Prevent nil pointer (state expects empty, when not defined)
@@ -301,19 +451,31 @@ func readFlowsFromPS(ps *zerotrust.ProtectSurface) flows {
 	f.incomingPSBlock = []basetypes.StringValue{}
 	f.outgoingPSAllow = []basetypes.StringValue{}
 	f.outgoingPSBlock = []basetypes.StringValue{}
+	f.incomingPSUnset = []basetypes.StringValue{}
+	f.outgoingPSUnset = []basetypes.StringValue{}
+
+	if ps == nil {
+		return f
+	}
 
 	for psID, flow := range ps.FlowsFromOtherPS {
-		if *flow.Allow {
+		switch {
+		case flow.Allow == nil:
+			f.incomingPSUnset = append(f.incomingPSUnset, basetypes.NewStringValue(psID))
+		case *flow.Allow:
 			f.incomingPSAllow = append(f.incomingPSAllow, basetypes.NewStringValue(psID))
-		} else {
+		default:
 			f.incomingPSBlock = append(f.incomingPSBlock, basetypes.NewStringValue(psID))
 		}
 	}
 
 	for psID, flow := range ps.FlowsToOtherPS {
-		if *flow.Allow {
+		switch {
+		case flow.Allow == nil:
+			f.outgoingPSUnset = append(f.outgoingPSUnset, basetypes.NewStringValue(psID))
+		case *flow.Allow:
 			f.outgoingPSAllow = append(f.outgoingPSAllow, basetypes.NewStringValue(psID))
-		} else {
+		default:
 			f.outgoingPSBlock = append(f.outgoingPSBlock, basetypes.NewStringValue(psID))
 		}
 	}
@@ -321,20 +483,49 @@ func readFlowsFromPS(ps *zerotrust.ProtectSurface) flows {
 	return f
 }
 
+// flowsDrift returns the flow attributes of the protectsurface which differ from the prior state
+func flowsDrift(ctx context.Context, prior *transactionflowResourceModel) func(ps *zerotrust.ProtectSurface) []string {
+	return func(ps *zerotrust.ProtectSurface) []string {
+		f := readFlowsFromPS(ps)
+
+		var drifted []string
+		for _, attr := range []struct {
+			name    string
+			prior   types.Set
+			current []basetypes.StringValue
+		}{
+			{"incoming_protectsurfaces_allow", prior.Incoming_protectsurfaces_allow, f.incomingPSAllow},
+			{"incoming_protectsurfaces_block", prior.Incoming_protectsurfaces_block, f.incomingPSBlock},
+			{"incoming_protectsurfaces_unset", prior.Incoming_protectsurfaces_unset, f.incomingPSUnset},
+			{"outgoing_protectsurfaces_allow", prior.Outgoing_protectsurfaces_allow, f.outgoingPSAllow},
+			{"outgoing_protectsurfaces_block", prior.Outgoing_protectsurfaces_block, f.outgoingPSBlock},
+			{"outgoing_protectsurfaces_unset", prior.Outgoing_protectsurfaces_unset, f.outgoingPSUnset},
+		} {
+			// A state of an older version of the provider can miss the attribute
+			if attr.prior.IsNull() && len(attr.current) == 0 {
+				continue
+			}
+
+			current, _ := types.SetValueFrom(ctx, types.StringType, attr.current)
+			if !attr.prior.Equal(current) {
+				drifted = append(drifted, attr.name)
+			}
+		}
+
+		return drifted
+	}
+}
+
 // setFlowsOnPS, get a ProtectSurface and a flows struct, and set the flows on the ProtectSurface
 func setFlowsOnPS(ps *zerotrust.ProtectSurface, flows flows) (*zerotrust.ProtectSurface, error) {
 
 	//Check for duplicates in incoming and outgoing, which is not allowed
-	for _, flow := range flows.incomingPSAllow {
-		if sliceContains(getSliceFromSetOfString(flows.incomingPSBlock), flow.ValueString()) {
-			return nil, fmt.Errorf("duplicate in incoming_protectsurfaces_allow and incoming_protectsurfaces_block, protectsurface ID: %s", flow.ValueString())
-		}
+	if err := checkDuplicateFlows("incoming", flows.incomingPSAllow, flows.incomingPSBlock, flows.incomingPSUnset); err != nil {
+		return nil, err
 	}
 
-	for _, flow := range flows.outgoingPSAllow {
-		if sliceContains(getSliceFromSetOfString(flows.outgoingPSBlock), flow.ValueString()) {
-			return nil, fmt.Errorf("duplicate in outgoing_protectsurfaces_allow and outgoing_protectsurfaces_block, protectsurface ID: %s", flow.ValueString())
-		}
+	if err := checkDuplicateFlows("outgoing", flows.outgoingPSAllow, flows.outgoingPSBlock, flows.outgoingPSUnset); err != nil {
+		return nil, err
 	}
 
 	ps.FlowsFromOtherPS = map[string]zerotrust.Flow{}
@@ -348,6 +539,10 @@ func setFlowsOnPS(ps *zerotrust.ProtectSurface, flows flows) (*zerotrust.Protect
 		ps.FlowsFromOtherPS[flow.ValueString()] = zerotrust.Flow{Allow: boolPtr(false)}
 	}
 
+	for _, flow := range flows.incomingPSUnset {
+		ps.FlowsFromOtherPS[flow.ValueString()] = zerotrust.Flow{}
+	}
+
 	for _, flow := range flows.outgoingPSAllow {
 		ps.FlowsToOtherPS[flow.ValueString()] = zerotrust.Flow{Allow: boolPtr(true)}
 	}
@@ -356,5 +551,102 @@ func setFlowsOnPS(ps *zerotrust.ProtectSurface, flows flows) (*zerotrust.Protect
 		ps.FlowsToOtherPS[flow.ValueString()] = zerotrust.Flow{Allow: boolPtr(false)}
 	}
 
+	for _, flow := range flows.outgoingPSUnset {
+		ps.FlowsToOtherPS[flow.ValueString()] = zerotrust.Flow{}
+	}
+
 	return ps, nil
 }
+
+// pruneUnconfiguredFlows removes the IDs of the configured flow sets from the planned flow sets which are not configured,
+// per direction, so a flow can move from e.g. the unset to the allow set. The planned sets become unknown when the
+// configured sets are not known yet, changed is true when the plan is modified.
+func pruneUnconfiguredFlows(ctx context.Context, config *transactionflowResourceModel, plan *transactionflowResourceModel) (changed bool, diags diag.Diagnostics) {
+	type flowSet struct {
+		configured types.Set
+		planned    *types.Set
+	}
+
+	for _, direction := range [][]flowSet{
+		{
+			{config.Incoming_protectsurfaces_allow, &plan.Incoming_protectsurfaces_allow},
+			{config.Incoming_protectsurfaces_block, &plan.Incoming_protectsurfaces_block},
+			{config.Incoming_protectsurfaces_unset, &plan.Incoming_protectsurfaces_unset},
+		},
+		{
+			{config.Outgoing_protectsurfaces_allow, &plan.Outgoing_protectsurfaces_allow},
+			{config.Outgoing_protectsurfaces_block, &plan.Outgoing_protectsurfaces_block},
+			{config.Outgoing_protectsurfaces_unset, &plan.Outgoing_protectsurfaces_unset},
+		},
+	} {
+		var configured []string
+		unknown := false
+
+		for _, set := range direction {
+			if set.configured.IsUnknown() {
+				unknown = true
+				continue
+			}
+
+			var ids []types.String
+			diags.Append(set.configured.ElementsAs(ctx, &ids, false)...)
+			for _, id := range ids {
+				if id.IsUnknown() {
+					unknown = true
+					continue
+				}
+				configured = append(configured, id.ValueString())
+			}
+		}
+
+		for _, set := range direction {
+			if !set.configured.IsNull() || set.planned.IsNull() || set.planned.IsUnknown() {
+				continue
+			}
+
+			var ids, kept []string
+			diags.Append(set.planned.ElementsAs(ctx, &ids, false)...)
+			for _, id := range ids {
+				if !sliceContains(configured, id) {
+					kept = append(kept, id)
+				}
+			}
+
+			switch {
+			case len(ids) == 0:
+				continue
+			case unknown:
+				*set.planned = types.SetUnknown(types.StringType)
+			case len(kept) == len(ids):
+				continue
+			default:
+				var d diag.Diagnostics
+				*set.planned, d = types.SetValueFrom(ctx, types.StringType, append([]string{}, kept...))
+				diags.Append(d...)
+			}
+			changed = true
+		}
+	}
+
+	return changed, diags
+}
+
+// checkDuplicateFlows returns an error when a protectsurface ID is in more than one of the allow, block and unset sets of the direction
+func checkDuplicateFlows(direction string, allow, block, unset []basetypes.StringValue) error {
+	seen := map[string]string{}
+
+	for _, set := range []struct {
+		name  string
+		flows []basetypes.StringValue
+	}{{"allow", allow}, {"block", block}, {"unset", unset}} {
+		for _, flow := range set.flows {
+			if other, ok := seen[flow.ValueString()]; ok && other != set.name {
+				return fmt.Errorf("duplicate in %s_protectsurfaces_%s and %s_protectsurfaces_%s, protectsurface ID: %s",
+					direction, other, direction, set.name, flow.ValueString())
+			}
+			seen[flow.ValueString()] = set.name
+		}
+	}
+
+	return nil
+}
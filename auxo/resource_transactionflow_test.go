@@ -0,0 +1,92 @@
+package auxo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestPruneUnconfiguredFlows(t *testing.T) {
+	ctx := context.Background()
+	set := func(ids ...string) types.Set {
+		s, _ := types.SetValueFrom(ctx, types.StringType, ids)
+		return s
+	}
+	null := types.SetNull(types.StringType)
+	unknown := types.SetUnknown(types.StringType)
+
+	tests := map[string]struct {
+		configAllow, configBlock, configUnset types.Set
+		planAllow, planBlock, planUnset       types.Set
+		wantAllow, wantBlock, wantUnset       types.Set
+		wantChanged                           bool
+	}{
+		"unset to allow": {
+			configAllow: set("ps2", "ps3"), configBlock: null, configUnset: null,
+			planAllow: set("ps2", "ps3"), planBlock: set(), planUnset: set("ps3", "ps4"),
+			wantAllow: set("ps2", "ps3"), wantBlock: set(), wantUnset: set("ps4"),
+			wantChanged: true,
+		},
+		"allow to block": {
+			configAllow: null, configBlock: set("ps2"), configUnset: null,
+			planAllow: set("ps2", "ps3"), planBlock: set("ps2"), planUnset: null,
+			wantAllow: set("ps3"), wantBlock: set("ps2"), wantUnset: null,
+			wantChanged: true,
+		},
+		"configured unset is kept": {
+			configAllow: set("ps2"), configBlock: null, configUnset: set("ps2"),
+			planAllow: set("ps2"), planBlock: set(), planUnset: set("ps2"),
+			wantAllow: set("ps2"), wantBlock: set(), wantUnset: set("ps2"),
+		},
+		"no duplicates": {
+			configAllow: set("ps2"), configBlock: null, configUnset: null,
+			planAllow: set("ps2"), planBlock: set(), planUnset: set("ps4"),
+			wantAllow: set("ps2"), wantBlock: set(), wantUnset: set("ps4"),
+		},
+		"unknown configured set": {
+			configAllow: unknown, configBlock: null, configUnset: null,
+			planAllow: unknown, planBlock: set(), planUnset: set("ps4"),
+			wantAllow: unknown, wantBlock: set(), wantUnset: unknown,
+			wantChanged: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := &transactionflowResourceModel{
+				Incoming_protectsurfaces_allow: tt.configAllow,
+				Incoming_protectsurfaces_block: tt.configBlock,
+				Incoming_protectsurfaces_unset: tt.configUnset,
+				Outgoing_protectsurfaces_allow: null,
+				Outgoing_protectsurfaces_block: null,
+				Outgoing_protectsurfaces_unset: null,
+			}
+			plan := &transactionflowResourceModel{
+				Incoming_protectsurfaces_allow: tt.planAllow,
+				Incoming_protectsurfaces_block: tt.planBlock,
+				Incoming_protectsurfaces_unset: tt.planUnset,
+				Outgoing_protectsurfaces_allow: set(),
+				Outgoing_protectsurfaces_block: set(),
+				Outgoing_protectsurfaces_unset: set("ps2"),
+			}
+
+			changed, diags := pruneUnconfiguredFlows(ctx, config, plan)
+			if diags.HasError() {
+				t.Fatalf("unexpected error %v", diags)
+			}
+
+			if changed != tt.wantChanged {
+				t.Errorf("expected changed %v, got %v", tt.wantChanged, changed)
+			}
+			if !plan.Incoming_protectsurfaces_allow.Equal(tt.wantAllow) || !plan.Incoming_protectsurfaces_block.Equal(tt.wantBlock) ||
+				!plan.Incoming_protectsurfaces_unset.Equal(tt.wantUnset) {
+				t.Errorf("expected allow %v, block %v and unset %v, got %v, %v and %v", tt.wantAllow, tt.wantBlock, tt.wantUnset,
+					plan.Incoming_protectsurfaces_allow, plan.Incoming_protectsurfaces_block, plan.Incoming_protectsurfaces_unset)
+			}
+			if !plan.Outgoing_protectsurfaces_unset.Equal(set("ps2")) {
+				t.Errorf("expected the outgoing flows to be unchanged, got %v", plan.Outgoing_protectsurfaces_unset)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Description: This file contains functions for resolving provider settings from the configuration or environment variables
+
+package auxo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Sources of a provider setting, used in the debug logging
+const (
+	sourceConfiguration = "provider configuration"
+	sourceProfile       = "ztctl profile"
+	sourceDefault       = "default"
+)
+
+// sourceEnvironment returns the source name of an environment variable
+func sourceEnvironment(name string) string {
+	return "environment variable " + name
+}
+
+// stringSetting returns the value of the attribute, the environment variable or the default (in that order) and its source
+func stringSetting(attribute types.String, env string, def string) (string, string) {
+	if attribute.ValueString() != "" {
+		return attribute.ValueString(), sourceConfiguration
+	}
+
+	if value := os.Getenv(env); value != "" {
+		return value, sourceEnvironment(env)
+	}
+
+	return def, sourceDefault
+}
+
+// boolSetting returns the value of the attribute, the environment variable or the default (in that order) and its source
+func boolSetting(attribute types.Bool, env string, def bool) (bool, string, error) {
+	if !attribute.IsNull() && !attribute.IsUnknown() {
+		return attribute.ValueBool(), sourceConfiguration, nil
+	}
+
+	if value := os.Getenv(env); value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return def, sourceDefault, fmt.Errorf("invalid value [%s] for %s, expected a boolean", value, env)
+		}
+		return b, sourceEnvironment(env), nil
+	}
+
+	return def, sourceDefault, nil
+}
+
+// float64Setting returns the value of the attribute, the environment variable or the default (in that order) and its source
+func float64Setting(attribute types.Float64, env string, def float64) (float64, string, error) {
+	if !attribute.IsNull() && !attribute.IsUnknown() {
+		return attribute.ValueFloat64(), sourceConfiguration, nil
+	}
+
+	if value := os.Getenv(env); value != "" {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return def, sourceDefault, fmt.Errorf("invalid value [%s] for %s, expected a number", value, env)
+		}
+		return f, sourceEnvironment(env), nil
+	}
+
+	return def, sourceDefault, nil
+}
+
+// durationSetting returns the value of the attribute, the environment variable or the default (in that order) and its source
+func durationSetting(attribute types.String, env string, def time.Duration) (time.Duration, string, error) {
+	value, source := stringSetting(attribute, env, "")
+	if source == sourceDefault {
+		return def, source, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return def, sourceDefault, fmt.Errorf("invalid duration [%s] from the %s, expected a positive duration like 30s", value, source)
+	}
+
+	return d, source, nil
+}
+
+// stringListSetting returns the value of the attribute or the environment variable, split by the OS path list separator, and its source
+func stringListSetting(ctx context.Context, attribute types.List, env string) ([]string, string, diag.Diagnostics) {
+	if !attribute.IsNull() && !attribute.IsUnknown() {
+		var values []string
+		diags := attribute.ElementsAs(ctx, &values, false)
+		return values, sourceConfiguration, diags
+	}
+
+	if value := os.Getenv(env); value != "" {
+		return filepath.SplitList(value), sourceEnvironment(env), nil
+	}
+
+	return nil, sourceDefault, nil
+}
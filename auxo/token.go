@@ -0,0 +1,164 @@
+// Description: This file contains functions for reading the API token from a file or an external credential helper
+
+package auxo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// defaultTokenCommandTimeout is the time a token command may take, when no timeout is configured
+const defaultTokenCommandTimeout = 30 * time.Second
+
+// tokenCache caches the tokens read from a file or command for the lifetime of the provider
+type tokenCache struct {
+	m      sync.Mutex
+	tokens map[string]string
+}
+
+// get returns the cached token for key, the token is read with load on first use
+func (c *tokenCache) get(key string, load func() (string, error)) (string, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if token, ok := c.tokens[key]; ok {
+		return token, nil
+	}
+
+	token, err := load()
+	if err != nil {
+		return "", err
+	}
+
+	if c.tokens == nil {
+		c.tokens = map[string]string{}
+	}
+	c.tokens[key] = token
+
+	return token, nil
+}
+
+// readTokenFile returns the token in the file, without surrounding whitespace
+func readTokenFile(fileName string) (string, error) {
+	content, err := readFile(fileName)
+	if err != nil {
+		return "", fmt.Errorf("unable to read token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", fileName)
+	}
+
+	return token, nil
+}
+
+// runTokenCommand runs the command and returns the token printed on stdout, without surrounding whitespace
+// The command is executed directly (not through a shell) and killed after the timeout.
+func runTokenCommand(ctx context.Context, command []string, timeout time.Duration) (string, error) {
+	if len(command) == 0 || command[0] == "" {
+		return "", errors.New("token command is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("token command %s did not finish within %s", command[0], timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("token command %s failed: %w: %s", command[0], err, msg)
+		}
+		return "", fmt.Errorf("token command %s failed: %w", command[0], err)
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("token command %s did not return a token", command[0])
+	}
+
+	return token, nil
+}
+
+// resolveToken returns the token and its source, in order of precedence the token, token_file and token_command
+// attributes followed by the AUXO_TOKEN, AUXO_TOKEN_FILE and AUXO_TOKEN_COMMAND environment variables
+// Tokens read from a file or command are cached for the lifetime of the provider.
+func (p *auxoProvider) resolveToken(ctx context.Context, data *auxoProviderModel) (string, string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	configured := 0
+	for _, set := range []bool{data.Token.ValueString() != "", data.TokenFile.ValueString() != "", len(data.TokenCommand.Elements()) > 0} {
+		if set {
+			configured++
+		}
+	}
+	if configured > 1 {
+		diags.AddError("Conflicting token configuration", "Only one of token, token_file and token_command can be set")
+		return "", "", diags
+	}
+
+	timeout, _, err := durationSetting(data.TokenCommandTimeout, "AUXO_TOKEN_COMMAND_TIMEOUT", defaultTokenCommandTimeout)
+	if err != nil {
+		diags.AddAttributeError(path.Root("token_command_timeout"), "Invalid token command timeout", err.Error())
+		return "", "", diags
+	}
+
+	var command []string
+	diags.Append(data.TokenCommand.ElementsAs(ctx, &command, false)...)
+
+	candidates := []struct {
+		source string
+		token  string
+		file   string
+		cmd    []string
+	}{
+		{source: sourceConfiguration, token: data.Token.ValueString()},
+		{source: sourceConfiguration + " (token_file)", file: data.TokenFile.ValueString()},
+		{source: sourceConfiguration + " (token_command)", cmd: command},
+		{source: sourceEnvironment("AUXO_TOKEN"), token: os.Getenv("AUXO_TOKEN")},
+		{source: sourceEnvironment("AUXO_TOKEN_FILE"), file: os.Getenv("AUXO_TOKEN_FILE")},
+		{source: sourceEnvironment("AUXO_TOKEN_COMMAND"), cmd: strings.Fields(os.Getenv("AUXO_TOKEN_COMMAND"))},
+	}
+
+	for _, c := range candidates {
+		var token string
+		var err error
+
+		switch {
+		case c.token != "":
+			return c.token, c.source, diags
+		case c.file != "":
+			token, err = p.tokens.get("file:"+c.file, func() (string, error) { return readTokenFile(c.file) })
+		case len(c.cmd) > 0:
+			token, err = p.tokens.get("command:"+strings.Join(c.cmd, "\x00"), func() (string, error) { return runTokenCommand(ctx, c.cmd, timeout) })
+		default:
+			continue
+		}
+
+		if err != nil {
+			diags.AddError("Unable to retrieve API token", "The token from the "+c.source+" could not be retrieved: "+err.Error())
+			return "", "", diags
+		}
+
+		return token, c.source, diags
+	}
+
+	return "", sourceDefault, diags
+}
@@ -0,0 +1,110 @@
+// Description: This file contains the coordinate type, a float64 which ignores tiny differences caused by round-tripping through the API
+
+package auxo
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// defaultCoordinatePrecision is the default precision (in degrees) below which coordinates are considered equal, about 1 cm
+const defaultCoordinatePrecision = 1e-7
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ basetypes.Float64Typable                    = coordinateType{}
+	_ basetypes.Float64ValuableWithSemanticEquals = coordinateValue{}
+)
+
+// coordinateType is the attribute type of a latitude or longitude
+type coordinateType struct {
+	basetypes.Float64Type
+}
+
+func (t coordinateType) Equal(o attr.Type) bool {
+	other, ok := o.(coordinateType)
+	if !ok {
+		return false
+	}
+
+	return t.Float64Type.Equal(other.Float64Type)
+}
+
+func (t coordinateType) String() string {
+	return "coordinateType"
+}
+
+func (t coordinateType) ValueFromFloat64(ctx context.Context, in basetypes.Float64Value) (basetypes.Float64Valuable, diag.Diagnostics) {
+	return coordinateValue{Float64Value: in}, nil
+}
+
+func (t coordinateType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.Float64Type.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	float64Value, ok := attrValue.(basetypes.Float64Value)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return coordinateValue{Float64Value: float64Value}, nil
+}
+
+func (t coordinateType) ValueType(ctx context.Context) attr.Value {
+	return coordinateValue{}
+}
+
+// coordinateValue is the value of a latitude or longitude
+// precision is the coordinate precision of the provider, which is only known on values created by the resource (e.g.
+// read from the API), values from the configuration or state use the precision of the value they are compared with.
+type coordinateValue struct {
+	basetypes.Float64Value
+	precision float64
+}
+
+// newCoordinateValue returns a known coordinateValue, with the coordinate precision of the provider
+func newCoordinateValue(value float64, precision float64) coordinateValue {
+	return coordinateValue{Float64Value: basetypes.NewFloat64Value(value), precision: precision}
+}
+
+func (v coordinateValue) Equal(o attr.Value) bool {
+	other, ok := o.(coordinateValue)
+	if !ok {
+		return false
+	}
+
+	return v.Float64Value.Equal(other.Float64Value)
+}
+
+func (v coordinateValue) Type(ctx context.Context) attr.Type {
+	return coordinateType{}
+}
+
+// Float64SemanticEquals returns true when the difference between the coordinates is below the coordinate precision
+func (v coordinateValue) Float64SemanticEquals(ctx context.Context, newValuable basetypes.Float64Valuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(coordinateValue)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error", fmt.Sprintf("Expected value type %T, got %T", v, newValuable))
+		return false, diags
+	}
+
+	precision := newValue.precision
+	if precision == 0 {
+		precision = v.precision
+	}
+	if precision == 0 {
+		precision = defaultCoordinatePrecision
+	}
+
+	return math.Abs(v.ValueFloat64()-newValue.ValueFloat64()) < precision, diags
+}
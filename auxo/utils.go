@@ -2,32 +2,58 @@ package auxo
 
 import (
 	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 )
 
 // apiError is the struct for the error returned by the go-auxo API
 type apiError struct {
+	Status  int    `json:"-"`
 	ID      string `json:"error_id"`
 	Name    string `json:"error_name"`
 	Message string `json:"error_message"`
 }
 
+// apiStatusErrorRegex matches the error of go-auxo for an unexpected HTTP status, with the status and the body
+var apiStatusErrorRegex = regexp.MustCompile(`(?s)^Not 200 or 201 ok, but (\d+), with body (.*)$`)
+
 // BoolPtr returns a pointer to a (given) bool
 func boolPtr(b bool) *bool {
 	return &b
 }
 
-// getAPIError returns an apiError struct from a go-auxo error
+// getAPIError returns an apiError struct from a go-auxo error, with the HTTP status and the error of the body
 func getAPIError(err error) apiError {
 	var apiErr apiError
-	//Workaround for API error messages from go-auxo
-	cleanError := strings.Replace(err.Error(), "Not 200 or 201 ok, but 404, with body ", "", -1)
-	json.Unmarshal([]byte(cleanError), &apiErr)
+
+	match := apiStatusErrorRegex.FindStringSubmatch(err.Error())
+	if match == nil {
+		return apiErr
+	}
+
+	json.Unmarshal([]byte(match[2]), &apiErr)
+	apiErr.Status, _ = strconv.Atoi(match[1])
+
 	return apiErr
 }
 
+// isNotFoundError returns true when a go-auxo error means the object does not exist (anymore)
+func isNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	status := getAPIError(err).Status
+
+	return status == http.StatusNotFound || status == http.StatusGone || strings.HasPrefix(err.Error(), "No protectsurface found")
+}
+
 // getSliceFromSetOfString converts a slice of basetypes.StringValue to a slice of string
 func getSliceFromSetOfString(values []basetypes.StringValue) []string {
 	result := []string{}
@@ -46,3 +72,24 @@ func sliceContains(slice []string, match string) bool {
 	}
 	return false
 }
+
+// isDeletionProtected returns the resource deletion_protection value, or the provider default when not set
+func isDeletionProtected(value types.Bool, providerDefault bool) bool {
+	if value.IsNull() || value.IsUnknown() {
+		return providerDefault
+	}
+	return value.ValueBool()
+}
+
+// isReadOnlyBlocked adds an error when the provider is in read-only mode, returns true when the write must be blocked
+func isReadOnlyBlocked(readOnly bool, action, object string, diags *diag.Diagnostics) bool {
+	if !readOnly {
+		return false
+	}
+
+	diags.AddError("Provider is in read-only mode",
+		"Unable to "+action+" "+object+", the provider is configured with read_only (or AUXO_READ_ONLY), which blocks every create, update and delete. "+
+			"Disable read_only to apply changes.")
+
+	return true
+}
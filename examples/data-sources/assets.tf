@@ -0,0 +1,3 @@
+data "auxo_assets" "firewalls" {
+  name_regex = "^fw"
+}
\ No newline at end of file
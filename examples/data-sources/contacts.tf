@@ -0,0 +1,4 @@
+data "auxo_contacts" "active_on2it" {
+  email_regex = "@on2it\\.net$"
+  status      = "normal"
+}
\ No newline at end of file
@@ -0,0 +1,8 @@
+data "auxo_flow_audit" "tenant" {}
+
+check "flows_consistent" {
+  assert {
+    condition     = length([for f in data.auxo_flow_audit.tenant.findings : f if f.kind == "contradictory"]) == 0
+    error_message = join("\n", [for f in data.auxo_flow_audit.tenant.findings : f.message if f.kind == "contradictory"])
+  }
+}
\ No newline at end of file
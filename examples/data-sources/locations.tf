@@ -0,0 +1,7 @@
+data "auxo_locations" "near_zaltbommel" {
+  near = {
+    latitude  = 51.7983645
+    longitude = 5.2548381
+    radius_km = 50
+  }
+}
\ No newline at end of file
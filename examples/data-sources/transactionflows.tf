@@ -0,0 +1,11 @@
+data "auxo_transactionflows" "all" {
+  format = "mermaid"
+}
+
+output "flow_diagram" {
+  value = data.auxo_transactionflows.all.rendered
+}
+
+data "auxo_transactionflows" "ad" {
+  protectsurface = auxo_protectsurface.ps_ad.id
+}
\ No newline at end of file
@@ -0,0 +1,12 @@
+terraform {
+  required_version = ">= 1.8.0"
+}
+
+resource "auxo_state" "webservers" {
+  description       = "Webservers"
+  protectsurface_id = auxo_protectsurface.ps_mail.id
+  location_id       = auxo_location.loc_zaltbommel.id
+  content_type      = "ipv4"
+  # Results in ["10.42.0.0/23"]
+  content = provider::auxo::aggregate_cidrs(["10.42.0.0/24", "10.42.1.0/25", "10.42.1.128/25"])
+}
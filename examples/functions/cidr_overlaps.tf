@@ -0,0 +1,12 @@
+terraform {
+  required_version = ">= 1.8.0"
+}
+
+variable "office_cidr" {
+  type = string
+
+  validation {
+    condition     = !provider::auxo::cidr_overlaps(var.office_cidr, "10.42.0.0/16")
+    error_message = "The office CIDR can not overlap with the datacenter."
+  }
+}
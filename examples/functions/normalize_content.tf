@@ -0,0 +1,8 @@
+terraform {
+  required_version = ">= 1.8.0"
+}
+
+output "normalized" {
+  # Returns "10.1.1.0/24"
+  value = provider::auxo::normalize_content("ipv4", "10.1.1.5/24")
+}
@@ -0,0 +1,10 @@
+terraform {
+  required_version = ">= 1.8.0"
+}
+
+resource "auxo_protectsurface" "webservers" {
+  name      = "Web Servers"
+  relevance = 3
+  # Results in "web-servers.prod"
+  uniqueness_key = provider::auxo::uniqueness_key("Web Servers", "PROD")
+}
@@ -0,0 +1,8 @@
+provider "auxo" {
+  defaults {
+    customer_labels = {
+      cost_center = "1234"
+    }
+    compliance_tags = ["ISO27001"]
+  }
+}
@@ -0,0 +1,3 @@
+provider "auxo" {
+  token_command = ["vault-auxo-token", "--tenant", "tenant1"]
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/on2itsecurity/terraform-provider-auxo/auxo"
 )
 
+// version is set by goreleaser with -X main.version
+var version = "dev"
+
 func main() {
 	var debug bool
 
@@ -17,7 +20,7 @@ func main() {
 
 	err := providerserver.Serve(
 		context.Background(),
-		auxo.New,
+		auxo.New(version),
 		providerserver.ServeOpts{
 			Debug:   debug,
 			Address: "registry.terraform.io/on2itsecurity/auxo",